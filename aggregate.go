@@ -0,0 +1,229 @@
+package fbnd
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxConcurrentFetches is the number of timetables that AllTimetables fetches at the same time.
+const maxConcurrentFetches = 4
+
+// AllTimetables returns the timetables of all degree programs of both semester cycles,
+// sorted by the ID of their degree program. The DegreeProgram of each Timetable is set.
+// The timetables are fetched concurrently; use WithRateLimit to not overwhelm the server.
+func (c *Client) AllTimetables(ctx context.Context) ([]*Timetable, error) {
+	var programs []DegreeProgram
+	for _, cycle := range []SemesterCycle{Winter, Summer} {
+		p, err := c.DegreePrograms(ctx, cycle)
+		if err != nil {
+			return nil, err
+		}
+		programs = append(programs, p...)
+	}
+
+	sort.SliceStable(programs, func(i, j int) bool {
+		return programs[i].ID < programs[j].ID
+	})
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		timetables = make([]*Timetable, len(programs))
+		wg         sync.WaitGroup
+		sem        = make(chan struct{}, maxConcurrentFetches)
+		mu         sync.Mutex
+		firstErr   error
+	)
+	for i := range programs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			timetable, err := c.TimetableForDegreeProgram(ctx, programs[i].ID)
+			if err != nil {
+				// Only the first error is reported, the others are likely caused by canceling.
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				mu.Unlock()
+				return
+			}
+			timetable.DegreeProgram = &programs[i]
+			timetables[i] = timetable
+		}(i)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+
+	return timetables, nil
+}
+
+// Merge returns a Timetable that contains the distinct courses of all timetables for
+// which keep returns true. Courses that are part of multiple timetables, like lectures
+// shared by several degree programs, are only contained once.
+// Like the timetables returned by TimetableForDegreeProgram, the days are sorted by their
+// weekday and the courses by their start. The DegreeProgram of the result is nil.
+func Merge(timetables []*Timetable, keep func(c Course) bool) *Timetable {
+	seen := make(map[Course]bool)
+	days := make(map[time.Weekday][]Course)
+
+	for _, t := range timetables {
+		for _, day := range t.Days {
+			for _, course := range day.Courses {
+				if seen[course] || !keep(course) {
+					continue
+				}
+				seen[course] = true
+				days[day.Weekday] = append(days[day.Weekday], course)
+			}
+		}
+	}
+
+	merged := &Timetable{}
+	for weekday, courses := range days {
+		sort.SliceStable(courses, func(i, j int) bool {
+			a, b := courses[i].Time, courses[j].Time
+			if a.HourStart != b.HourStart {
+				return a.HourStart < b.HourStart
+			}
+			if a.HourEnd != b.HourEnd {
+				return a.HourEnd < b.HourEnd
+			}
+			return courses[i].NameShort < courses[j].NameShort
+		})
+		merged.Days = append(merged.Days, TimetableDay{Weekday: weekday, Courses: courses})
+	}
+	sort.Slice(merged.Days, func(i, j int) bool {
+		return merged.Days[i].Weekday < merged.Days[j].Weekday
+	})
+
+	return merged
+}
+
+// RoomTimetable returns the courses of all degree programs of both semester cycles
+// that take place in the given room, see AllTimetables and Merge.
+// Rooms are compared case-insensitively and ignoring spaces, so "z2.10" matches "Z 2.10".
+func (c *Client) RoomTimetable(ctx context.Context, room string) (*Timetable, error) {
+	timetables, err := c.AllTimetables(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	room = NormalizeRoom(room)
+	return Merge(timetables, func(c Course) bool {
+		return NormalizeRoom(c.Room) == room
+	}), nil
+}
+
+// ProfessorTimetable returns the courses of all degree programs of both semester cycles
+// that are held by the given professor, see AllTimetables and Merge.
+// The name matches a course if it equals its ProfessorShort or is part of its
+// ProfessorLong, both case-insensitively, so "mül" and "Müller" match "Prof. Dr. Müller".
+func (c *Client) ProfessorTimetable(ctx context.Context, name string) (*Timetable, error) {
+	timetables, err := c.AllTimetables(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimSpace(name)
+	lower := strings.ToLower(name)
+	return Merge(timetables, func(c Course) bool {
+		return strings.EqualFold(c.ProfessorShort, name) || strings.Contains(strings.ToLower(c.ProfessorLong), lower)
+	}), nil
+}
+
+// NormalizeRoom returns room in upper case without spaces, so that "z2.10" equals "Z 2.10".
+func NormalizeRoom(room string) string {
+	return strings.ToUpper(strings.Join(strings.Fields(room), ""))
+}
+
+// FreeRooms returns the rooms of the timetables, see Rooms, in which no course
+// takes place at any time during window.
+func FreeRooms(timetables []*Timetable, window Time) []string {
+	occupied := make(map[string]bool)
+	for _, t := range timetables {
+		for _, day := range t.Days {
+			for _, course := range day.Courses {
+				if course.Time.Overlaps(window) {
+					occupied[NormalizeRoom(course.Room)] = true
+				}
+			}
+		}
+	}
+
+	var free []string
+	for _, room := range Rooms(timetables) {
+		if !occupied[NormalizeRoom(room)] {
+			free = append(free, room)
+		}
+	}
+	return free
+}
+
+// Professor is a person that holds courses.
+type Professor struct {
+	Short string `json:"short"`
+	Long  string `json:"long"`
+}
+
+// Rooms returns the distinct rooms in which the courses of the timetables take place,
+// sorted by their name. Unknown rooms are left out.
+func Rooms(timetables []*Timetable) []string {
+	seen := make(map[string]bool)
+	var rooms []string
+
+	for _, t := range timetables {
+		for _, day := range t.Days {
+			for _, course := range day.Courses {
+				if course.Room == "" || course.Room == "Unknown" || seen[course.Room] {
+					continue
+				}
+				seen[course.Room] = true
+				rooms = append(rooms, course.Room)
+			}
+		}
+	}
+
+	sort.Strings(rooms)
+	return rooms
+}
+
+// Professors returns the distinct professors that hold the courses of the timetables,
+// sorted by their short name.
+func Professors(timetables []*Timetable) []Professor {
+	seen := make(map[Professor]bool)
+	var professors []Professor
+
+	for _, t := range timetables {
+		for _, day := range t.Days {
+			for _, course := range day.Courses {
+				p := Professor{Short: course.ProfessorShort, Long: course.ProfessorLong}
+				if p.Short == "" || seen[p] {
+					continue
+				}
+				seen[p] = true
+				professors = append(professors, p)
+			}
+		}
+	}
+
+	sort.Slice(professors, func(i, j int) bool {
+		if professors[i].Short != professors[j].Short {
+			return professors[i].Short < professors[j].Short
+		}
+		return professors[i].Long < professors[j].Long
+	})
+	return professors
+}
@@ -0,0 +1,114 @@
+package fbnd_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/n9v9/fbnd"
+	"github.com/n9v9/fbnd/fbndtest"
+)
+
+func TestAllTimetables(t *testing.T) {
+	client := newTestServer(t).Client()
+
+	timetables, err := client.AllTimetables(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []fbnd.ID
+	for _, v := range timetables {
+		if v.DegreeProgram == nil {
+			t.Fatal("want degree program to be set")
+		}
+		ids = append(ids, v.DegreeProgram.ID)
+	}
+	if want := []fbnd.ID{"BI1", "BI2", "MI1"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("want timetables for %v, got %v", want, ids)
+	}
+
+	if want, got := []string{"B 1.10", "Z 2.10"}, fbnd.Rooms(timetables); !reflect.DeepEqual(want, got) {
+		t.Fatalf("want rooms %v, got %v", want, got)
+	}
+
+	want := []fbnd.Professor{{Short: "Mül", Long: "Prof. Dr. Müller"}, {Short: "Sch", Long: "Prof. Dr. Schmidt"}}
+	if got := fbnd.Professors(timetables); !reflect.DeepEqual(want, got) {
+		t.Fatalf("want professors %v, got %v", want, got)
+	}
+}
+
+func TestRoomTimetable(t *testing.T) {
+	client := newTestServer(t).Client()
+
+	// MA1 is part of the timetables of BI1 and BI2, but must only be contained once.
+	timetable, err := client.RoomTimetable(context.Background(), "b1.10")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []fbnd.TimetableDay{{Weekday: fbndtest.SampleCourses()[0].Time.Weekday, Courses: fbndtest.SampleCourses()[:1]}}
+	if !reflect.DeepEqual(timetable.Days, want) {
+		t.Fatalf("want days %v, got %v", want, timetable.Days)
+	}
+}
+
+func TestFreeRooms(t *testing.T) {
+	timetables, err := newTestServer(t).Client().AllTimetables(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type testCase struct {
+		name   string
+		window fbnd.Time
+		want   []string
+	}
+
+	testCases := []testCase{
+		{name: "BothFree", window: fbnd.Time{Weekday: time.Monday, Start: fbnd.Clock{Hour: 10}, End: fbnd.Clock{Hour: 12}}, want: []string{"B 1.10", "Z 2.10"}},
+		{name: "OneOccupied", window: fbnd.Time{Weekday: time.Monday, Start: fbnd.Clock{Hour: 9}, End: fbnd.Clock{Hour: 10}}, want: []string{"Z 2.10"}},
+		{name: "OtherOccupied", window: fbnd.Time{Weekday: time.Wednesday, Start: fbnd.Clock{Hour: 10}, End: fbnd.Clock{Hour: 11}}, want: []string{"B 1.10"}},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			if got := fbnd.FreeRooms(timetables, test.window); !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("want %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestFreeRoomsNormalized(t *testing.T) {
+	// The room is written differently by the courses, but it is the same one.
+	timetables := []*fbnd.Timetable{{Days: []fbnd.TimetableDay{
+		{Weekday: time.Monday, Courses: []fbnd.Course{{NameShort: "MA1", Room: "B1.10", Time: fbnd.FB03Slots.Time(time.Monday, 8, 10)}}},
+		{Weekday: time.Tuesday, Courses: []fbnd.Course{{NameShort: "PR1", Room: "B 1.10", Time: fbnd.FB03Slots.Time(time.Tuesday, 8, 10)}}},
+	}}}
+
+	window := fbnd.Time{Weekday: time.Monday, Start: fbnd.Clock{Hour: 9}, End: fbnd.Clock{Hour: 10}}
+	if got := fbnd.FreeRooms(timetables, window); len(got) != 0 {
+		t.Fatalf("want no free rooms, got %v", got)
+	}
+}
+
+func TestProfessorTimetable(t *testing.T) {
+	client := newTestServer(t).Client()
+
+	for _, name := range []string{"sch", "Schmidt", "prof. dr. schmidt"} {
+		timetable, err := client.ProfessorTimetable(context.Background(), name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := []fbnd.TimetableDay{
+			{Weekday: time.Monday, Courses: fbndtest.SampleCourses()[1:2]},
+			{Weekday: time.Wednesday, Courses: fbndtest.SampleCourses()[2:3]},
+		}
+		if !reflect.DeepEqual(timetable.Days, want) {
+			t.Fatalf("want days %v for %q, got %v", want, name, timetable.Days)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package fbnd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrNotCached is returned by a Client using a Cache in CacheOffline mode
+// when the requested data has not been cached yet.
+var ErrNotCached = errors.New("response is not cached")
+
+// CacheMode controls how a Cache is used by a Client.
+type CacheMode int
+
+const (
+	// CacheNormal returns cached responses that are younger than the TTL of the cache
+	// and fetches all others. If fetching fails, a stale cached response is returned
+	// if there is one.
+	CacheNormal CacheMode = iota
+	// CacheRefresh always fetches and updates the cache.
+	CacheRefresh
+	// CacheOffline never fetches and returns cached responses regardless of their age.
+	CacheOffline
+)
+
+// Cache stores the responses of the requests made by a Client on disk.
+// Each response is stored in its own file that is named after the hash of the request,
+// that is its method, URL and form parameters.
+type Cache struct {
+	dir  string
+	ttl  time.Duration
+	mode CacheMode
+}
+
+// NewCache returns a Cache that stores responses in dir. The directory is created
+// when the first response is stored. Cached responses older than ttl are considered stale.
+func NewCache(dir string, ttl time.Duration, mode CacheMode) *Cache {
+	return &Cache{
+		dir:  dir,
+		ttl:  ttl,
+		mode: mode,
+	}
+}
+
+// DefaultCacheDir returns the directory fbnd inside the cache directory of the user,
+// see os.UserCacheDir.
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "fbnd"), nil
+}
+
+// get returns the cached response for key, whether it was found and whether it is
+// younger than the TTL of the cache.
+func (c *Cache) get(key string) (body []byte, fresh bool, ok bool) {
+	path := filepath.Join(c.dir, key)
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, false, false
+	}
+	body, err = os.ReadFile(path)
+	if err != nil {
+		return nil, false, false
+	}
+
+	return body, time.Since(info.ModTime()) < c.ttl, true
+}
+
+// put stores body under key.
+// The body is written to a temporary file first, so that concurrent readers never
+// see a partially written response.
+func (c *Cache) put(key string, body []byte) error {
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return err
+	}
+
+	f, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(body); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(f.Name(), filepath.Join(c.dir, key))
+}
+
+// Clear removes all cached responses.
+func (c *Cache) Clear() error {
+	err := os.RemoveAll(c.dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// cacheKey returns the name under which the response of a request is cached.
+func cacheKey(method, rawURL string, form url.Values) string {
+	// Encode sorts the form by key, so the same parameters always result in the same key.
+	sum := sha256.Sum256([]byte(method + "\n" + rawURL + "\n" + form.Encode()))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,68 @@
+package fbnd_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/n9v9/fbnd"
+)
+
+func TestCache(t *testing.T) {
+	server := newTestServer(t)
+	dir := t.TempDir()
+	ctx := context.Background()
+
+	fetch := func(ttl time.Duration, mode fbnd.CacheMode) error {
+		client := server.Client(fbnd.WithCache(fbnd.NewCache(dir, ttl, mode)))
+		_, err := client.DegreePrograms(ctx, fbnd.Winter)
+		return err
+	}
+	wantRequests := func(want int) {
+		t.Helper()
+		if got := server.Requests(); got != want {
+			t.Fatalf("want %d requests, got %d", want, got)
+		}
+	}
+
+	if err := fetch(time.Hour, fbnd.CacheOffline); !errors.Is(err, fbnd.ErrNotCached) {
+		t.Fatalf("want error %v, got %v", fbnd.ErrNotCached, err)
+	}
+	wantRequests(0)
+
+	if err := fetch(time.Hour, fbnd.CacheNormal); err != nil {
+		t.Fatal(err)
+	}
+	wantRequests(1)
+
+	// The cached response is fresh.
+	if err := fetch(time.Hour, fbnd.CacheNormal); err != nil {
+		t.Fatal(err)
+	}
+	wantRequests(1)
+
+	if err := fetch(time.Hour, fbnd.CacheRefresh); err != nil {
+		t.Fatal(err)
+	}
+	wantRequests(2)
+
+	// The cached response is stale.
+	if err := fetch(0, fbnd.CacheNormal); err != nil {
+		t.Fatal(err)
+	}
+	wantRequests(3)
+
+	server.Close()
+
+	// The stale cached response is used as the server can not be reached.
+	if err := fetch(0, fbnd.CacheNormal); err != nil {
+		t.Fatal(err)
+	}
+	if err := fetch(0, fbnd.CacheOffline); err != nil {
+		t.Fatal(err)
+	}
+	if err := fetch(0, fbnd.CacheRefresh); err == nil {
+		t.Fatal("want error when refreshing without a server, got nil")
+	}
+}
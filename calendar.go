@@ -0,0 +1,231 @@
+package fbnd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/n9v9/fbnd/holiday"
+
+	// Embed the time zone database, so that Location is available on every system.
+	_ "time/tzdata"
+)
+
+// Location is the time zone in which all courses take place.
+var Location = mustLoadLocation("Europe/Berlin")
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+	return loc
+}
+
+// Date returns midnight of the given day in Location.
+func Date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, Location)
+}
+
+// dateOf returns midnight of the day of t in Location.
+func dateOf(t time.Time) time.Time {
+	year, month, day := t.In(Location).Date()
+	return Date(year, month, day)
+}
+
+// Period is a range of days from Start to End, both inclusive.
+// Only the dates of Start and End in Location are considered.
+type Period struct {
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+}
+
+// Contains reports whether the day of t lies within p.
+func (p Period) Contains(t time.Time) bool {
+	day := dateOf(t)
+	return !day.Before(dateOf(p.Start)) && !day.After(dateOf(p.End))
+}
+
+const dateFormat = "2006-01-02"
+
+// periodJSON is the JSON representation of Period, which only contains the dates.
+type periodJSON struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
+
+// MarshalJSON encodes p as an object with the dates of Start and End in the form 2006-01-02.
+func (p Period) MarshalJSON() ([]byte, error) {
+	return json.Marshal(periodJSON{
+		Start: p.Start.In(Location).Format(dateFormat),
+		End:   p.End.In(Location).Format(dateFormat),
+	})
+}
+
+// UnmarshalJSON decodes an object with the dates of Start and End in the form 2006-01-02.
+// The resulting times are midnight in Location.
+func (p *Period) UnmarshalJSON(data []byte) error {
+	var v periodJSON
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	start, err := time.ParseInLocation(dateFormat, v.Start, Location)
+	if err != nil {
+		return fmt.Errorf("invalid start of period: %w", err)
+	}
+	end, err := time.ParseInLocation(dateFormat, v.End, Location)
+	if err != nil {
+		return fmt.Errorf("invalid end of period: %w", err)
+	}
+	if end.Before(start) {
+		return fmt.Errorf("period ends on %s before it starts on %s", v.End, v.Start)
+	}
+
+	p.Start, p.End = start, end
+	return nil
+}
+
+// SemesterCalendar describes when the lectures of a Semester take place.
+type SemesterCalendar struct {
+	// Lectures is the lecture period of the semester.
+	Lectures Period `json:"lectures"`
+	// Breaks are periods within Lectures without any courses, like the Christmas break.
+	Breaks []Period `json:"breaks"`
+}
+
+// IsLectureDay reports whether courses take place on the day of t,
+// which is the case if it lies within the lecture period, but neither within
+// a break nor on a public holiday, see IsHoliday.
+func (c SemesterCalendar) IsLectureDay(t time.Time) bool {
+	if !c.Lectures.Contains(t) || IsHoliday(t) {
+		return false
+	}
+	for _, v := range c.Breaks {
+		if v.Contains(t) {
+			return false
+		}
+	}
+	return true
+}
+
+// DefaultCalendar returns the usual calendar of the semester of the given cycle
+// that starts in year:
+//
+//   - The winter semester has lectures from the fourth Monday of September until
+//     18 weeks later, with a break from December 23rd until January 6th.
+//   - The summer semester has lectures from the third Monday of March until
+//     16 weeks later.
+//
+// Use Semester.Calendar to set the exact dates if they differ.
+func DefaultCalendar(cycle SemesterCycle, year int) SemesterCalendar {
+	if cycle == Winter {
+		start := nthMonday(year, time.September, 4)
+		return SemesterCalendar{
+			Lectures: Period{Start: start, End: start.AddDate(0, 0, 18*7-3)},
+			Breaks: []Period{
+				{Start: Date(year, time.December, 23), End: Date(year+1, time.January, 6)},
+			},
+		}
+	}
+
+	start := nthMonday(year, time.March, 3)
+	return SemesterCalendar{
+		Lectures: Period{Start: start, End: start.AddDate(0, 0, 16*7-3)},
+	}
+}
+
+// IsHoliday reports whether the day of t in Location is a public holiday in
+// North Rhine-Westphalia, on which no courses take place.
+// Use the holiday package to get the name of the holiday.
+func IsHoliday(t time.Time) bool {
+	_, ok := holiday.Lookup(t.In(Location))
+	return ok
+}
+
+// CycleOf returns the semester cycle that t falls into:
+// Summer from March until August and Winter from September until February.
+func CycleOf(t time.Time) SemesterCycle {
+	if month := t.In(Location).Month(); month >= time.March && month <= time.August {
+		return Summer
+	}
+	return Winter
+}
+
+// nthMonday returns the nth Monday of the month, starting at 1.
+func nthMonday(year int, month time.Month, n int) time.Time {
+	first := Date(year, month, 1)
+	offset := (int(time.Monday) - int(first.Weekday()) + 7) % 7
+	return first.AddDate(0, 0, offset+(n-1)*7)
+}
+
+// LectureCalendar returns the Calendar of s if it is set and DefaultCalendar otherwise.
+func (s Semester) LectureCalendar() SemesterCalendar {
+	if s.Calendar != nil {
+		return *s.Calendar
+	}
+	return DefaultCalendar(s.Cycle, s.Year)
+}
+
+// Occurrence is a Course that takes place on a concrete date.
+type Occurrence struct {
+	Course Course    `json:"course"`
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+}
+
+// IsLectureDay reports whether the courses of t take place on the given day.
+// If the DegreeProgram of t is known, this is the case on the lecture days of its
+// semester, see Semester.LectureCalendar; otherwise on every day that is not a
+// public holiday, see IsHoliday.
+func (t *Timetable) IsLectureDay(day time.Time) bool {
+	if t.DegreeProgram != nil {
+		return t.DegreeProgram.Semester.LectureCalendar().IsLectureDay(day)
+	}
+	return !IsHoliday(day)
+}
+
+// Occurrences returns all occurrences of the courses of t that start at or after from
+// and before to, sorted by their start. The times are in Location.
+// Days on which no lectures take place are skipped, see Timetable.IsLectureDay.
+func (t *Timetable) Occurrences(from, to time.Time) []Occurrence {
+	var occurrences []Occurrence
+
+	for day := dateOf(from); day.Before(to); day = day.AddDate(0, 0, 1) {
+		if !t.IsLectureDay(day) {
+			continue
+		}
+
+		for _, timetableDay := range t.Days {
+			if timetableDay.Weekday != day.Weekday() {
+				continue
+			}
+
+			for _, course := range timetableDay.Courses {
+				clockStart, clockEnd := course.Time.clocks()
+				start := atClock(day, clockStart)
+				if start.Before(from) || !start.Before(to) {
+					continue
+				}
+				occurrences = append(occurrences, Occurrence{
+					Course: course,
+					Start:  start,
+					End:    atClock(day, clockEnd),
+				})
+			}
+		}
+	}
+
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		return occurrences[i].Start.Before(occurrences[j].Start)
+	})
+
+	return occurrences
+}
+
+// atClock returns the time of the given clock on day in Location.
+func atClock(day time.Time, clock Clock) time.Time {
+	year, month, date := day.Date()
+	return time.Date(year, month, date, clock.Hour, clock.Minute, 0, 0, Location)
+}
@@ -0,0 +1,174 @@
+package fbnd
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDefaultCalendar(t *testing.T) {
+	type testCase struct {
+		name      string
+		cycle     SemesterCycle
+		year      int
+		wantStart time.Time
+		wantEnd   time.Time
+	}
+
+	testCases := []testCase{
+		{name: "Winter2023", cycle: Winter, year: 2023, wantStart: Date(2023, time.September, 25), wantEnd: Date(2024, time.January, 26)},
+		{name: "Summer2023", cycle: Summer, year: 2023, wantStart: Date(2023, time.March, 20), wantEnd: Date(2023, time.July, 7)},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			got := DefaultCalendar(test.cycle, test.year).Lectures
+			if !got.Start.Equal(test.wantStart) || !got.End.Equal(test.wantEnd) {
+				t.Fatalf("want lectures from %v to %v, got %v to %v", test.wantStart, test.wantEnd, got.Start, got.End)
+			}
+		})
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	timetable := &Timetable{
+		DegreeProgram: &DegreeProgram{
+			ID:       "BI1",
+			Semester: Semester{Cycle: Winter, Year: 2023, Term: 1},
+		},
+		Days: []TimetableDay{
+			{Weekday: time.Monday, Courses: []Course{{NameShort: "MA1", Time: FB03Slots.Time(time.Monday, 8, 10)}}},
+			{Weekday: time.Friday, Courses: []Course{{NameShort: "PR1", Time: FB03Slots.Time(time.Friday, 14, 16)}}},
+		},
+	}
+
+	type testCase struct {
+		name      string
+		from      time.Time
+		to        time.Time
+		wantStart []time.Time
+	}
+
+	at := func(year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, Location)
+	}
+
+	testCases := []testCase{
+		{
+			name:      "BeforeLecturePeriod",
+			from:      Date(2023, time.September, 18),
+			to:        Date(2023, time.September, 25),
+			wantStart: nil,
+		},
+		{
+			name:      "FirstWeek",
+			from:      Date(2023, time.September, 25),
+			to:        Date(2023, time.October, 2),
+			wantStart: []time.Time{at(2023, time.September, 25, 8, 15), at(2023, time.September, 29, 14, 15)},
+		},
+		{
+			name:      "AcrossDaylightSavingTime",
+			from:      Date(2023, time.October, 27),
+			to:        Date(2023, time.October, 31),
+			wantStart: []time.Time{at(2023, time.October, 27, 14, 15), at(2023, time.October, 30, 8, 15)},
+		},
+		{
+			name:      "ChristmasBreak",
+			from:      Date(2023, time.December, 22),
+			to:        Date(2024, time.January, 9),
+			wantStart: []time.Time{at(2023, time.December, 22, 14, 15), at(2024, time.January, 8, 8, 15)},
+		},
+		{
+			name:      "FromWithinADay",
+			from:      at(2023, time.September, 25, 9, 0),
+			to:        at(2023, time.September, 29, 14, 15),
+			wantStart: nil,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			got := timetable.Occurrences(test.from, test.to)
+			if len(got) != len(test.wantStart) {
+				t.Fatalf("want %d occurrences, got %v", len(test.wantStart), got)
+			}
+			for i, v := range got {
+				if !v.Start.Equal(test.wantStart[i]) {
+					t.Fatalf("want occurrence %d to start at %v, got %v", i, test.wantStart[i], v.Start)
+				}
+				if v.End.Sub(v.Start) != v.Course.Time.Duration() {
+					t.Fatalf("want occurrence %d to take %v, got %v", i, v.Course.Time.Duration(), v.End.Sub(v.Start))
+				}
+			}
+		})
+	}
+}
+
+func TestIsLectureDay(t *testing.T) {
+	calendar := DefaultCalendar(Winter, 2023)
+
+	type testCase struct {
+		name string
+		day  time.Time
+		want bool
+	}
+
+	testCases := []testCase{
+		{name: "RegularDay", day: Date(2023, time.October, 2), want: true},
+		{name: "TagDerDeutschenEinheit", day: Date(2023, time.October, 3), want: false},
+		{name: "Allerheiligen", day: time.Date(2023, time.November, 1, 10, 0, 0, 0, Location), want: false},
+		{name: "ChristmasBreak", day: Date(2024, time.January, 5), want: false},
+		{name: "AfterLecturePeriod", day: Date(2024, time.February, 5), want: false},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			if got := calendar.IsLectureDay(test.day); got != test.want {
+				t.Fatalf("want %v, got %v", test.want, got)
+			}
+		})
+	}
+
+	// Without a degree program only holidays are considered.
+	timetable := &Timetable{}
+	if timetable.IsLectureDay(Date(2023, time.October, 3)) || !timetable.IsLectureDay(Date(2024, time.February, 5)) {
+		t.Fatal("want only holidays to be excluded for a timetable without degree program")
+	}
+}
+
+func TestPeriodJSON(t *testing.T) {
+	want := Period{Start: Date(2023, time.December, 23), End: Date(2024, time.January, 6)}
+
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"start":"2023-12-23","end":"2024-01-06"}` {
+		t.Fatalf("want dates only, got %s", data)
+	}
+
+	var got Period
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if !got.Start.Equal(want.Start) || !got.End.Equal(want.End) {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+
+	if err := json.Unmarshal([]byte(`{"start":"2024-01-06","end":"2023-12-23"}`), &got); err == nil {
+		t.Fatal("want error for a period that ends before it starts")
+	}
+}
+
+func TestCycleOf(t *testing.T) {
+	for day, want := range map[time.Time]SemesterCycle{
+		Date(2023, time.February, 28): Winter,
+		Date(2023, time.March, 1):     Summer,
+		Date(2023, time.August, 31):   Summer,
+		Date(2023, time.September, 1): Winter,
+	} {
+		if got := CycleOf(day); got != want {
+			t.Errorf("want %s on %v, got %s", want, day, got)
+		}
+	}
+}
@@ -0,0 +1,326 @@
+package fbnd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const defaultUserAgent = "fbnd (+https://github.com/n9v9/fbnd)"
+
+// Client fetches degree programs and timetables from a Source.
+// By default, the timetable website is scraped; use the Options passed to NewClient
+// to point the Client at a different URL or at a JSON API.
+//
+// A Client is safe for concurrent use by multiple goroutines.
+type Client struct {
+	baseURL    string
+	httpClient *http.Client
+	userAgent  string
+	logger     *log.Logger
+	jsonAPI    bool
+	source     Source
+	cache      *Cache
+	retry      RetryPolicy
+	limiter    *rateLimiter
+	slots      SlotTable
+	calendars  map[calendarKey]SemesterCalendar
+}
+
+// calendarKey identifies a semester for which a SemesterCalendar is configured.
+type calendarKey struct {
+	cycle SemesterCycle
+	year  int
+}
+
+// Option configures a Client, see NewClient.
+type Option func(c *Client)
+
+// WithBaseURL sets the URL that is requested.
+// When scraping the website, this is the URL of the stundenplan.php page,
+// when using a JSON API, see WithJSONAPI, this is the base URL of the API.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithHTTPClient sets the http.Client that is used to make requests.
+// This can be used to configure timeouts or a custom transport.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithUserAgent sets the value of the User-Agent header of each request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithLogger sets a logger to which every request is logged.
+// By default, nothing is logged.
+func WithLogger(logger *log.Logger) Option {
+	return func(c *Client) {
+		c.logger = logger
+	}
+}
+
+// WithJSONAPI makes the Client query a JSON API at the base URL instead of scraping
+// the website. This is the API served by the command fbnd serve, which scrapes the
+// website itself. The JSON API that is used internally by the website is not supported.
+// The following endpoints are queried, using the JSON representation of the types in
+// this package:
+//
+//	GET <base URL>/programs?cycle=<summer|winter>  -> []DegreeProgram
+//	GET <base URL>/programs/<ID>/timetable         -> Timetable
+func WithJSONAPI() Option {
+	return func(c *Client) {
+		c.jsonAPI = true
+	}
+}
+
+// WithCache makes the Client store responses in cache and answer requests from it,
+// depending on the CacheMode of cache.
+// By default, nothing is cached.
+func WithCache(cache *Cache) Option {
+	return func(c *Client) {
+		c.cache = cache
+	}
+}
+
+// WithRetry makes the Client retry failed requests according to policy.
+// By default, requests are not retried.
+func WithRetry(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// WithRateLimit makes the Client wait at least interval between starting two requests,
+// regardless of how many goroutines use the Client.
+// By default, requests are not limited.
+func WithRateLimit(interval time.Duration) Option {
+	return func(c *Client) {
+		c.limiter = &rateLimiter{interval: interval}
+	}
+}
+
+// WithSlotTable sets the table that is used to compute the clock times of courses.
+// By default, FB03Slots is used.
+func WithSlotTable(slots SlotTable) Option {
+	return func(c *Client) {
+		c.slots = slots
+	}
+}
+
+// WithCalendar sets the calendar of the semester of the given cycle that starts in year,
+// for example to configure the exact lecture period and breaks of a university.
+// It is set as Semester.Calendar of every DegreeProgram of that semester returned
+// by the Client, replacing the calendar of the Source.
+// By default, the calendar of the Source or DefaultCalendar is used.
+func WithCalendar(cycle SemesterCycle, year int, calendar SemesterCalendar) Option {
+	return func(c *Client) {
+		if c.calendars == nil {
+			c.calendars = make(map[calendarKey]SemesterCalendar)
+		}
+		c.calendars[calendarKey{cycle: cycle, year: year}] = calendar
+	}
+}
+
+// WithSource makes the Client delegate all calls to source.
+// The options regarding HTTP have no effect on source.
+func WithSource(source Source) Option {
+	return func(c *Client) {
+		c.source = source
+	}
+}
+
+// NewClient returns a new Client configured with the given options.
+func NewClient(opts ...Option) *Client {
+	c := &Client{
+		baseURL:    timetableURL,
+		httpClient: http.DefaultClient,
+		userAgent:  defaultUserAgent,
+		slots:      FB03Slots,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.source == nil {
+		if c.jsonAPI {
+			c.source = jsonSource{c: c}
+		} else {
+			c.source = htmlSource{c: c}
+		}
+	}
+
+	return c
+}
+
+// DefaultClient is the Client used by the package level functions DegreePrograms
+// and TimetableForDegreeProgram.
+var DefaultClient = NewClient()
+
+// DegreePrograms returns all degree programs for which timetables are available
+// and that fall into the given cycle.
+func (c *Client) DegreePrograms(ctx context.Context, cycle SemesterCycle) ([]DegreeProgram, error) {
+	programs, err := c.source.DegreePrograms(ctx, cycle)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range programs {
+		c.setCalendar(&programs[i].Semester)
+	}
+
+	return programs, nil
+}
+
+// TimetableForDegreeProgram returns a Timetable that contains all courses for the given degree program.
+// The days inside Timetable are sorted by their weekday and the courses inside each day are sorted
+// by their start hour.
+// The ID can be obtained by calling DegreePrograms.
+func (c *Client) TimetableForDegreeProgram(ctx context.Context, id ID) (*Timetable, error) {
+	timetable, err := c.source.TimetableForDegreeProgram(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if timetable.DegreeProgram != nil {
+		c.setCalendar(&timetable.DegreeProgram.Semester)
+	}
+	// FillDegreeProgram has to go through the Client as well, so that the calendar is set.
+	timetable.source = c
+
+	return timetable, nil
+}
+
+// setCalendar sets the calendar of s if one is configured, see WithCalendar.
+func (c *Client) setCalendar(s *Semester) {
+	if calendar, ok := c.calendars[calendarKey{cycle: s.Cycle, year: s.Year}]; ok {
+		s.Calendar = &calendar
+	}
+}
+
+// fetch sends a request with the given method to rawURL and returns the body of the response.
+// If form is not nil, it is sent URL encoded as the body of the request.
+// If the Client has a Cache, the response is taken from and stored in it.
+func (c *Client) fetch(ctx context.Context, method, rawURL string, form url.Values) ([]byte, error) {
+	if c.cache == nil {
+		return c.doRetry(ctx, method, rawURL, form)
+	}
+
+	key := cacheKey(method, rawURL, form)
+	cached, fresh, ok := c.cache.get(key)
+
+	switch c.cache.mode {
+	case CacheOffline:
+		if !ok {
+			return nil, fmt.Errorf("%s %s %s: %w", method, rawURL, form.Encode(), ErrNotCached)
+		}
+		return cached, nil
+	case CacheNormal:
+		if fresh {
+			return cached, nil
+		}
+	}
+
+	body, err := c.doRetry(ctx, method, rawURL, form)
+	if err != nil {
+		// Rather show outdated data than nothing, unless the caller gave up
+		// or explicitly asked for fresh data.
+		if ok && c.cache.mode == CacheNormal && ctx.Err() == nil {
+			c.logf("using stale cached response for %s %s %s: %v", method, rawURL, form.Encode(), err)
+			return cached, nil
+		}
+		return nil, err
+	}
+
+	if err := c.cache.put(key, body); err != nil {
+		c.logf("could not cache response for %s %s %s: %v", method, rawURL, form.Encode(), err)
+	}
+
+	return body, nil
+}
+
+// doRetry calls do until it succeeds, returns an error that is not worth retrying
+// or the maximum number of attempts of the RetryPolicy of the Client is reached.
+func (c *Client) doRetry(ctx context.Context, method, rawURL string, form url.Values) ([]byte, error) {
+	for attempt := 1; ; attempt++ {
+		body, err := c.do(ctx, method, rawURL, form)
+		if err == nil || attempt >= c.retry.MaxAttempts || !retryable(err) || ctx.Err() != nil {
+			return body, err
+		}
+
+		delay := c.retry.delay(attempt)
+		c.logf("retrying %s %s %s in %v after attempt %d failed: %v", method, rawURL, form.Encode(), delay, attempt, err)
+		if err := sleep(ctx, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// do sends a request with the given method to rawURL and returns the body of the response.
+// If form is not nil, it is sent URL encoded as the body of the request.
+// Responses with a status code other than 200 result in a *StatusError.
+func (c *Client) do(ctx context.Context, method, rawURL string, form url.Values) ([]byte, error) {
+	if c.limiter != nil {
+		if err := c.limiter.wait(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, body)
+	if err != nil {
+		return nil, err
+	}
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	start := time.Now()
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		c.logf("%s %s %s: %v", method, rawURL, form.Encode(), err)
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	c.logf("%s %s %s: %s in %v", method, rawURL, form.Encode(), resp.Status, time.Since(start))
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{
+			Method:     method,
+			URL:        rawURL,
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+		}
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+// logf logs the message if the Client has a logger.
+func (c *Client) logf(format string, v ...any) {
+	if c.logger != nil {
+		c.logger.Printf(format, v...)
+	}
+}
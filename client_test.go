@@ -0,0 +1,38 @@
+package fbnd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientOptions(t *testing.T) {
+	var gotUserAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUserAgent = r.UserAgent()
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	client := NewClient(
+		WithBaseURL(server.URL),
+		WithHTTPClient(server.Client()),
+		WithUserAgent("test-agent"),
+		WithJSONAPI(),
+	)
+
+	if _, err := client.DegreePrograms(context.Background(), Summer); err != nil {
+		t.Fatal(err)
+	}
+	if gotUserAgent != "test-agent" {
+		t.Fatalf("want user agent %q, got %q", "test-agent", gotUserAgent)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.DegreePrograms(ctx, Summer); !errors.Is(err, context.Canceled) {
+		t.Fatalf("want error %v, got %v", context.Canceled, err)
+	}
+}
@@ -0,0 +1,144 @@
+package fbnd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Clock is a time of day with a precision of minutes.
+// Its JSON representation is a string like "08:15".
+type Clock struct {
+	Hour   int
+	Minute int
+}
+
+// ClockOf returns the Clock of t.
+func ClockOf(t time.Time) Clock {
+	return Clock{Hour: t.Hour(), Minute: t.Minute()}
+}
+
+// ParseClock parses a clock like "10:15" or a full hour like "10".
+func ParseClock(s string) (Clock, error) {
+	if !strings.Contains(s, ":") {
+		hour, err := strconv.Atoi(s)
+		if err != nil || hour < 0 || hour > 23 {
+			return Clock{}, fmt.Errorf("invalid clock %q", s)
+		}
+		return Clock{Hour: hour}, nil
+	}
+
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return Clock{}, fmt.Errorf("invalid clock %q: %w", s, err)
+	}
+	return ClockOf(t), nil
+}
+
+func (c Clock) String() string {
+	return fmt.Sprintf("%02d:%02d", c.Hour, c.Minute)
+}
+
+// IsZero reports whether c is midnight, which is also the value of a Time that
+// was decoded from JSON without clock times.
+func (c Clock) IsZero() bool {
+	return c == Clock{}
+}
+
+// Before reports whether c is before other.
+func (c Clock) Before(other Clock) bool {
+	return c.minutes() < other.minutes()
+}
+
+// Sub returns the duration c-other.
+func (c Clock) Sub(other Clock) time.Duration {
+	return time.Duration(c.minutes()-other.minutes()) * time.Minute
+}
+
+func (c Clock) minutes() int {
+	return c.Hour*60 + c.Minute
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (c Clock) MarshalText() ([]byte, error) {
+	return []byte(c.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (c *Clock) UnmarshalText(text []byte) error {
+	t, err := time.Parse("15:04", string(text))
+	if err != nil {
+		return fmt.Errorf("invalid clock %q: %w", text, err)
+	}
+	*c = ClockOf(t)
+	return nil
+}
+
+// Slot is the real start and end of one column of a timetable.
+type Slot struct {
+	Start Clock `json:"start"`
+	End   Clock `json:"end"`
+}
+
+// SlotTable maps the start hour of each column of a timetable, like 8 for the
+// column 8-9, to the Slot it really takes place in.
+// Faculties have their own tables as their lecture blocks differ.
+type SlotTable map[int]Slot
+
+// FB03Slots contains the lecture blocks of FB03. Each block of 90 minutes
+// covers two columns of the timetable, for example the columns 8-9 and 9-10
+// make up the block from 08:15 to 09:45.
+//
+// The table is not taken from an official schedule but assumes blocks with breaks
+// of 15 minutes and a lunch break from 13:15 to 14:15, which lies between the
+// columns 13-14 and 14-15. A course in both of these columns is therefore shown to
+// last across the lunch break. Use WithSlotTable if the blocks differ.
+var FB03Slots = SlotTable{
+	8:  {Start: Clock{8, 15}, End: Clock{9, 0}},
+	9:  {Start: Clock{9, 0}, End: Clock{9, 45}},
+	10: {Start: Clock{10, 0}, End: Clock{10, 45}},
+	11: {Start: Clock{10, 45}, End: Clock{11, 30}},
+	12: {Start: Clock{11, 45}, End: Clock{12, 30}},
+	13: {Start: Clock{12, 30}, End: Clock{13, 15}},
+	14: {Start: Clock{14, 15}, End: Clock{15, 0}},
+	15: {Start: Clock{15, 0}, End: Clock{15, 45}},
+	16: {Start: Clock{16, 0}, End: Clock{16, 45}},
+	17: {Start: Clock{16, 45}, End: Clock{17, 30}},
+	18: {Start: Clock{17, 45}, End: Clock{18, 30}},
+	19: {Start: Clock{18, 30}, End: Clock{19, 15}},
+}
+
+// slot returns the Slot for the column that starts at hour.
+// Hours that are not part of s take the full hour.
+func (s SlotTable) slot(hour int) Slot {
+	if slot, ok := s[hour]; ok {
+		return slot
+	}
+	return Slot{Start: Clock{Hour: hour}, End: Clock{Hour: hour + 1}}
+}
+
+// Time returns the Time of a course that takes place on weekday from the column
+// that starts at hourStart until the column that ends at hourEnd.
+func (s SlotTable) Time(weekday time.Weekday, hourStart, hourEnd int) Time {
+	return Time{
+		Weekday:   weekday,
+		HourStart: hourStart,
+		HourEnd:   hourEnd,
+		Start:     s.slot(hourStart).Start,
+		End:       s.slot(hourEnd - 1).End,
+	}
+}
+
+// fill sets the clock times of all courses in t that have none, which is the
+// case when t was decoded from JSON that predates them.
+func (s SlotTable) fill(t *Timetable) {
+	for i := range t.Days {
+		for j := range t.Days[i].Courses {
+			v := &t.Days[i].Courses[j].Time
+			if v.Start.IsZero() && v.End.IsZero() {
+				*v = s.Time(v.Weekday, v.HourStart, v.HourEnd)
+			}
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package fbnd
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestSlotTableTime(t *testing.T) {
+	type testCase struct {
+		name         string
+		hourStart    int
+		hourEnd      int
+		wantStart    Clock
+		wantEnd      Clock
+		wantDuration time.Duration
+	}
+
+	testCases := []testCase{
+		{name: "FirstBlock", hourStart: 8, hourEnd: 10, wantStart: Clock{8, 15}, wantEnd: Clock{9, 45}, wantDuration: 90 * time.Minute},
+		{name: "SingleColumn", hourStart: 11, hourEnd: 12, wantStart: Clock{10, 45}, wantEnd: Clock{11, 30}, wantDuration: 45 * time.Minute},
+		{name: "AcrossLunch", hourStart: 12, hourEnd: 15, wantStart: Clock{11, 45}, wantEnd: Clock{15, 0}, wantDuration: 195 * time.Minute},
+		{name: "OutsideOfTable", hourStart: 20, hourEnd: 22, wantStart: Clock{20, 0}, wantEnd: Clock{22, 0}, wantDuration: 2 * time.Hour},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			got := FB03Slots.Time(time.Monday, test.hourStart, test.hourEnd)
+			if got.Start != test.wantStart || got.End != test.wantEnd {
+				t.Fatalf("want %v - %v, got %v - %v", test.wantStart, test.wantEnd, got.Start, got.End)
+			}
+			if got.Duration() != test.wantDuration {
+				t.Fatalf("want duration %v, got %v", test.wantDuration, got.Duration())
+			}
+		})
+	}
+}
+
+func TestTimeContains(t *testing.T) {
+	courseTime := FB03Slots.Time(time.Tuesday, 8, 10)
+
+	// 2022-10-11 is a Tuesday.
+	type testCase struct {
+		instant time.Time
+		want    bool
+	}
+
+	testCases := []testCase{
+		{instant: time.Date(2022, 10, 11, 8, 0, 0, 0, time.UTC), want: false},
+		{instant: time.Date(2022, 10, 11, 8, 15, 0, 0, time.UTC), want: true},
+		{instant: time.Date(2022, 10, 11, 9, 44, 0, 0, time.UTC), want: true},
+		{instant: time.Date(2022, 10, 11, 9, 45, 0, 0, time.UTC), want: false},
+		{instant: time.Date(2022, 10, 12, 8, 30, 0, 0, time.UTC), want: false},
+	}
+
+	for _, test := range testCases {
+		if got := courseTime.Contains(test.instant); got != test.want {
+			t.Errorf("want Contains(%v) to be %v, got %v", test.instant, test.want, got)
+		}
+	}
+}
+
+func TestTimeJSON(t *testing.T) {
+	want := FB03Slots.Time(time.Friday, 14, 16)
+
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got Time
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got != want {
+		t.Fatalf("want %v, got %v", want, got)
+	}
+
+	// JSON without clock times falls back to the full hours.
+	var old Time
+	if err := json.Unmarshal([]byte(`{"weekday":5,"hourStart":14,"hourEnd":16}`), &old); err != nil {
+		t.Fatal(err)
+	}
+	if old.Duration() != 2*time.Hour {
+		t.Fatalf("want duration %v, got %v", 2*time.Hour, old.Duration())
+	}
+}
+
+func TestTimeOverlaps(t *testing.T) {
+	courseTime := FB03Slots.Time(time.Tuesday, 10, 12)
+
+	type testCase struct {
+		other Time
+		want  bool
+	}
+
+	testCases := []testCase{
+		{other: FB03Slots.Time(time.Tuesday, 8, 10), want: false},
+		{other: FB03Slots.Time(time.Tuesday, 11, 13), want: true},
+		{other: Time{Weekday: time.Tuesday, Start: Clock{11, 30}, End: Clock{12, 0}}, want: false},
+		{other: Time{Weekday: time.Tuesday, Start: Clock{9, 0}, End: Clock{14, 0}}, want: true},
+		{other: FB03Slots.Time(time.Wednesday, 10, 12), want: false},
+	}
+
+	for _, test := range testCases {
+		if got := courseTime.Overlaps(test.other); got != test.want {
+			t.Errorf("want Overlaps(%v) to be %v, got %v", test.other, test.want, got)
+		}
+	}
+}
+
+func TestParseClock(t *testing.T) {
+	type testCase struct {
+		s       string
+		want    Clock
+		wantErr bool
+	}
+
+	testCases := []testCase{
+		{s: "10", want: Clock{10, 0}},
+		{s: "10:15", want: Clock{10, 15}},
+		{s: "9:00", want: Clock{9, 0}},
+		{s: "24", wantErr: true},
+		{s: "10:75", wantErr: true},
+		{s: "ten", wantErr: true},
+	}
+
+	for _, test := range testCases {
+		got, err := ParseClock(test.s)
+		if (err != nil) != test.wantErr || got != test.want {
+			t.Errorf("want ParseClock(%q) to be %v (error: %v), got %v (%v)", test.s, test.want, test.wantErr, got, err)
+		}
+	}
+}
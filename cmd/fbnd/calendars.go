@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/n9v9/fbnd"
+)
+
+// semesterCalendar is an entry of the calendars file, for example:
+//
+//	[
+//	  {
+//	    "cycle": "Winter",
+//	    "year": 2023,
+//	    "lectures": {"start": "2023-09-25", "end": "2024-01-26"},
+//	    "breaks": [{"start": "2023-12-23", "end": "2024-01-06"}]
+//	  }
+//	]
+type semesterCalendar struct {
+	Cycle fbnd.SemesterCycle `json:"cycle"`
+	Year  int                `json:"year"`
+	fbnd.SemesterCalendar
+}
+
+// defaultCalendarsFile returns the path of the calendars file in the user's config directory.
+func defaultCalendarsFile() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "fbnd", "calendars.json"), nil
+}
+
+// calendarOptions reads the calendars file at path and returns an option for each entry.
+// If path is empty, the default calendars file is read if it exists.
+func calendarOptions(path string) ([]fbnd.Option, error) {
+	optional := path == ""
+	if optional {
+		var err error
+		if path, err = defaultCalendarsFile(); err != nil {
+			return nil, nil
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if optional && errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("could not read calendars: %w", err)
+	}
+
+	var calendars []semesterCalendar
+	if err := json.Unmarshal(data, &calendars); err != nil {
+		return nil, fmt.Errorf("could not parse calendars in %s: %w", path, err)
+	}
+
+	opts := make([]fbnd.Option, 0, len(calendars))
+	for _, v := range calendars {
+		if v.Cycle != fbnd.Summer && v.Cycle != fbnd.Winter {
+			return nil, fmt.Errorf("invalid cycle %q in %s, expected %s or %s", v.Cycle, path, fbnd.Summer, fbnd.Winter)
+		}
+		opts = append(opts, fbnd.WithCalendar(v.Cycle, v.Year, v.SemesterCalendar))
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/n9v9/fbnd"
+	"github.com/spf13/cobra"
+)
+
+func cmdConflicts() *cobra.Command {
+	var groups bool
+
+	cmd := &cobra.Command{
+		Use:   "conflicts <ID>...",
+		Short: "List courses of degree programs that take place at the same time",
+		Long: `List courses of degree programs that take place at the same time
+
+The courses of all given degree programs are compared with each other. Parallel groups
+of the same course, like the exercises PR1-A and PR1-B, are alternatives of which only
+one is attended, so their overlaps are not listed unless --groups is given.
+
+The command exits with status 2 if there are conflicts and with status 1 if it failed,
+so it can be used in scripts.`,
+		Args:   cobra.MinimumNArgs(1),
+		PreRun: initClient,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runConflicts(cmd.Context(), args, groups); err != nil {
+				printError(err)
+				os.Exit(exitCode(err))
+			}
+		},
+	}
+
+	cmd.Flags().BoolVar(&groups, "groups", false, "Also list overlaps of parallel groups of the same course")
+
+	return cmd
+}
+
+func runConflicts(ctx context.Context, ids []string, groups bool) error {
+	var courses []fbnd.Course
+	for _, id := range ids {
+		id := fbnd.ID(strings.ToUpper(id))
+		timetable, err := client.TimetableForDegreeProgram(ctx, id)
+		if err != nil {
+			return err
+		}
+		if len(timetable.Days) == 0 {
+			return fmt.Errorf("could find no courses for degree program with id %s", id)
+		}
+		for _, day := range timetable.Days {
+			courses = append(courses, day.Courses...)
+		}
+	}
+
+	conflicts := []fbnd.Conflict{}
+	for _, v := range fbnd.Conflicts(courses) {
+		if groups || !parallelGroups(v.A, v.B) {
+			conflicts = append(conflicts, v)
+		}
+	}
+
+	if outputJSON {
+		if err := json.NewEncoder(os.Stdout).Encode(conflicts); err != nil {
+			return err
+		}
+	} else if len(conflicts) == 0 {
+		fmt.Println("No conflicts")
+	} else {
+		conflicting := color.New(color.FgRed)
+		for _, v := range conflicts {
+			conflicting.Fprintln(color.Output, "! "+formatCourse(v.A))
+			fmt.Fprintln(color.Output, "  "+formatCourse(v.B))
+		}
+	}
+
+	if len(conflicts) > 0 {
+		return fmt.Errorf("%w: %d", errConflicts, len(conflicts))
+	}
+	return nil
+}
+
+// parallelGroups reports whether a and b are different groups of the same course
+// and lesson, like the exercises PR1-A and PR1-B.
+func parallelGroups(a, b fbnd.Course) bool {
+	nameA, groupA := a.Group()
+	nameB, groupB := b.Group()
+	return groupA != "" && groupB != "" && groupA != groupB && nameA == nameB && a.Lesson == b.Lesson
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/n9v9/fbnd"
+)
+
+func TestParallelGroups(t *testing.T) {
+	type testCase struct {
+		name string
+		a, b fbnd.Course
+		want bool
+	}
+
+	tests := []testCase{
+		{
+			name: "different groups",
+			a:    fbnd.Course{NameShort: "PR1-A", Lesson: fbnd.Exercise},
+			b:    fbnd.Course{NameShort: "PR1-B", Lesson: fbnd.Exercise},
+			want: true,
+		},
+		{
+			name: "same group",
+			a:    fbnd.Course{NameShort: "PR1-A", Lesson: fbnd.Exercise},
+			b:    fbnd.Course{NameShort: "PR1-A", Lesson: fbnd.Exercise},
+			want: false,
+		},
+		{
+			name: "different lessons",
+			a:    fbnd.Course{NameShort: "PR1-A", Lesson: fbnd.Exercise},
+			b:    fbnd.Course{NameShort: "PR1-B", Lesson: fbnd.Internship},
+			want: false,
+		},
+		{
+			name: "different courses",
+			a:    fbnd.Course{NameShort: "PR1-A", Lesson: fbnd.Exercise},
+			b:    fbnd.Course{NameShort: "MA1-B", Lesson: fbnd.Exercise},
+			want: false,
+		},
+		{
+			name: "without groups",
+			a:    fbnd.Course{NameShort: "PR1", Lesson: fbnd.Lecture},
+			b:    fbnd.Course{NameShort: "MA1", Lesson: fbnd.Lecture},
+			want: false,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := parallelGroups(test.a, test.b); got != test.want {
+				t.Fatalf("want %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	if got := exitCode(fmt.Errorf("%w: %d", errConflicts, 2)); got != 2 {
+		t.Fatalf("want exit code 2 for conflicts, got %d", got)
+	}
+	if got := exitCode(errors.New("request failed")); got != 1 {
+		t.Fatalf("want exit code 1 for other errors, got %d", got)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/fatih/color"
+	"github.com/n9v9/fbnd"
+	"github.com/spf13/cobra"
+)
+
+func cmdDiff() *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff <old.json> <new.json>",
+		Short: "Show the changes between two timetables",
+		Long: `Show the changes between two timetables
+
+This command expects two files that contain a timetable in JSON format, like the
+output of the time command with the --json flag. Courses are matched by their short
+name and lesson, so that changes of the room, professor and time are shown as such.`,
+		Args: cobra.ExactArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runDiff(args[0], args[1]); err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+		},
+	}
+}
+
+func runDiff(oldPath, newPath string) error {
+	old, err := readTimetable(oldPath)
+	if err != nil {
+		return err
+	}
+	new, err := readTimetable(newPath)
+	if err != nil {
+		return err
+	}
+
+	diff := fbnd.Diff(old, new)
+
+	if outputJSON {
+		return json.NewEncoder(os.Stdout).Encode(diff)
+	}
+
+	printDiff(color.Output, diff)
+	return nil
+}
+
+// readTimetable reads a timetable in JSON format from the file at path.
+func readTimetable(path string) (*fbnd.Timetable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var timetable fbnd.Timetable
+	if err := json.Unmarshal(data, &timetable); err != nil {
+		return nil, fmt.Errorf("could not parse timetable in %s: %w", path, err)
+	}
+
+	return &timetable, nil
+}
+
+// printDiff prints added courses in green, removed ones in red and modified ones
+// in yellow, followed by their changed fields.
+func printDiff(w io.Writer, diff fbnd.TimetableDiff) {
+	if diff.Empty() {
+		fmt.Fprintln(w, "No changes")
+		return
+	}
+
+	added := color.New(color.FgGreen)
+	removed := color.New(color.FgRed)
+	modified := color.New(color.FgYellow)
+
+	for _, v := range diff.Removed {
+		removed.Fprintln(w, "- "+formatCourse(v))
+	}
+	for _, v := range diff.Added {
+		added.Fprintln(w, "+ "+formatCourse(v))
+	}
+	for _, v := range diff.Modified {
+		modified.Fprintln(w, "~ "+formatCourse(v.Old))
+		for _, change := range v.Changes {
+			fmt.Fprintf(w, "    %s: %s -> %s\n", change.Field, change.Old, change.New)
+		}
+	}
+}
+
+// formatCourse returns a single line that describes c.
+func formatCourse(c fbnd.Course) string {
+	return fmt.Sprintf("%s | %s | %s | %s | %s", c.Time, c.NameShort, c.Lesson, c.ProfessorShort, c.Room)
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/n9v9/fbnd"
+	"github.com/n9v9/fbnd/ical"
+	"github.com/spf13/cobra"
+)
+
+func cmdExport() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the timetable for a specific degree program into other formats",
+	}
+
+	cmd.AddCommand(cmdExportICS())
+
+	return cmd
+}
+
+func cmdExportICS() *cobra.Command {
+	var output string
+
+	cmd := &cobra.Command{
+		Use:   "ics",
+		Short: "Export the timetable for a specific degree program as an iCalendar file",
+		Long: `Export the timetable for a specific degree program as an iCalendar file
+
+Each course becomes a weekly repeating event during the lecture period of the semester,
+excluding days without lectures. The file can be imported into most calendar applications.
+Importing it again after the timetable changed updates the existing events.
+
+This command expects the ID of the degree program for which to export the timetable.
+If you do not know the ID, you can see all available ones by calling the list command.`,
+		Args:   cobra.ExactArgs(1),
+		PreRun: initClient,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runExportICS(cmd.Context(), args[0], output); err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().StringVarP(&output, "output", "o", "", "File to write to, - for stdout (default \"<ID>.ics\")")
+
+	return cmd
+}
+
+func runExportICS(ctx context.Context, id, output string) error {
+	timetable, err := client.TimetableForDegreeProgram(ctx, fbnd.ID(id))
+	if err != nil {
+		return err
+	}
+	if len(timetable.Days) == 0 {
+		return fmt.Errorf("could find no courses for degree program with id %s", id)
+	}
+	// The semester of the degree program is needed for the lecture period.
+	if err := timetable.FillDegreeProgramContext(ctx); err != nil {
+		return err
+	}
+
+	if output == "" {
+		output = string(timetable.DegreeProgram.ID) + ".ics"
+	}
+
+	if output == "-" {
+		return ical.Encode(os.Stdout, timetable, ical.Options{})
+	}
+
+	f, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	if err := ical.Encode(f, timetable, ical.Options{}); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
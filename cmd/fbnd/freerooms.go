@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/n9v9/fbnd"
+	"github.com/spf13/cobra"
+)
+
+func cmdFreeRooms() *cobra.Command {
+	var day, from, to, building string
+
+	cmd := &cobra.Command{
+		Use:   "free-rooms",
+		Short: "List all rooms in which no course takes place during a time window",
+		Long: `List all rooms in which no course takes place during a time window
+
+The courses of all degree programs of the current semester are considered, so only
+rooms in which at least one course takes place are known.
+
+The day is a weekday like Tue, Tuesday or Di, the times are clocks like 10:15 or full
+hours like 10. For example, to find a room for Tuesday from 10 to 12 o'clock in building Z:
+
+  fbnd free-rooms --day Tue --from 10 --to 12 --building Z`,
+		Args:   cobra.NoArgs,
+		PreRun: initClient,
+		Run: func(cmd *cobra.Command, _ []string) {
+			if err := runFreeRooms(cmd.Context(), day, from, to, building); err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&day, "day", "", "Weekday of the time window (default today)")
+	cmd.Flags().StringVar(&from, "from", "", "Start of the time window")
+	cmd.Flags().StringVar(&to, "to", "", "End of the time window")
+	cmd.Flags().StringVar(&building, "building", "", "Only list rooms whose name starts with this prefix, like Z")
+	cmd.MarkFlagRequired("from")
+	cmd.MarkFlagRequired("to")
+
+	return cmd
+}
+
+func runFreeRooms(ctx context.Context, day, from, to, building string) error {
+	now := time.Now().In(fbnd.Location)
+
+	window := fbnd.Time{Weekday: now.Weekday()}
+	if day != "" {
+		weekday, err := parseWeekday(day)
+		if err != nil {
+			return err
+		}
+		window.Weekday = weekday
+	}
+
+	var err error
+	if window.Start, err = fbnd.ParseClock(from); err != nil {
+		return err
+	}
+	if window.End, err = fbnd.ParseClock(to); err != nil {
+		return err
+	}
+	if !window.Start.Before(window.End) {
+		return errors.New("the time window has to end after it starts")
+	}
+
+	timetables, err := client.AllTimetables(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Only the rooms of the current semester are relevant.
+	cycle := fbnd.CycleOf(now)
+	var current []*fbnd.Timetable
+	for _, v := range timetables {
+		if v.DegreeProgram.Semester.Cycle == cycle {
+			current = append(current, v)
+		}
+	}
+
+	rooms := []string{}
+	prefix := fbnd.NormalizeRoom(building)
+	for _, v := range fbnd.FreeRooms(current, window) {
+		if strings.HasPrefix(fbnd.NormalizeRoom(v), prefix) {
+			rooms = append(rooms, v)
+		}
+	}
+
+	if outputJSON {
+		return json.NewEncoder(os.Stdout).Encode(rooms)
+	}
+
+	if len(rooms) == 0 {
+		fmt.Printf("No free rooms on %s from %s to %s\n", window.Weekday, window.Start, window.End)
+		return nil
+	}
+	for _, v := range rooms {
+		fmt.Println(v)
+	}
+
+	return nil
+}
+
+var germanWeekdays = map[string]time.Weekday{
+	"so": time.Sunday,
+	"mo": time.Monday,
+	"di": time.Tuesday,
+	"mi": time.Wednesday,
+	"do": time.Thursday,
+	"fr": time.Friday,
+	"sa": time.Saturday,
+}
+
+// parseWeekday parses an English weekday like Tuesday, abbreviated to at least three
+// letters like Tue, or a German abbreviation like Di as used by the timetable website.
+func parseWeekday(s string) (time.Weekday, error) {
+	lower := strings.ToLower(s)
+
+	if weekday, ok := germanWeekdays[lower]; ok {
+		return weekday, nil
+	}
+	if len(lower) >= 3 {
+		for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+			if strings.HasPrefix(strings.ToLower(weekday.String()), lower) {
+				return weekday, nil
+			}
+		}
+	}
+
+	return 0, fmt.Errorf("invalid weekday %q, expected for example Tue, Tuesday or Di", s)
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseWeekday(t *testing.T) {
+	type testCase struct {
+		s       string
+		want    time.Weekday
+		wantErr bool
+	}
+
+	testCases := []testCase{
+		{s: "Tue", want: time.Tuesday},
+		{s: "tuesday", want: time.Tuesday},
+		{s: "Di", want: time.Tuesday},
+		{s: "thu", want: time.Thursday},
+		{s: "Do", want: time.Thursday},
+		{s: "t", wantErr: true},
+		{s: "Tuesdays", wantErr: true},
+	}
+
+	for _, test := range testCases {
+		got, err := parseWeekday(test.s)
+		if (err != nil) != test.wantErr || (!test.wantErr && got != test.want) {
+			t.Errorf("want parseWeekday(%q) to be %v (error: %v), got %v (%v)", test.s, test.want, test.wantErr, got, err)
+		}
+	}
+
+	if _, err := parseWeekday("Tuesdays"); err == nil || !strings.Contains(err.Error(), `"Tuesdays"`) {
+		t.Fatalf("want error quoting the input %q, got %v", "Tuesdays", err)
+	}
+}
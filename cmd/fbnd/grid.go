@@ -0,0 +1,483 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+	"github.com/n9v9/fbnd"
+	"github.com/n9v9/fbnd/holiday"
+)
+
+// printGrid prints the courses of timetable as a grid of the weekdays and hours
+// that is at most width characters wide, see renderGrid.
+func printGrid(timetable *fbnd.Timetable, width int) {
+	for _, line := range renderGrid(timetable, width, gridOptions{now: time.Now().In(fbnd.Location), course: -1}) {
+		fmt.Fprintln(color.Output, line)
+	}
+}
+
+// gridLabelWidth is the width of the column with the hours.
+const gridLabelWidth = 4
+
+// gridHeaderLines is the number of lines of the grid above the first hour.
+const gridHeaderLines = 3
+
+// gridOptions configures what renderGrid highlights.
+type gridOptions struct {
+	// now is the time by which today and its current and next courses are highlighted.
+	now time.Time
+	// day, if not nil, is the weekday that is underlined in the header, and course is the
+	// index of its course that is shown as selected, unless it is negative.
+	day    *time.Weekday
+	course int
+	// matches, if not nil, are the courses found by a search, which are highlighted
+	// instead of the current and next courses. All other courses are dimmed.
+	matches map[fbnd.Course]bool
+}
+
+// gridWeekdays returns the weekdays that are shown in the grid of timetable: Monday
+// to Saturday and Sunday if it has courses.
+func gridWeekdays(timetable *fbnd.Timetable) []time.Weekday {
+	weekdays := []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday}
+	for _, day := range timetable.Days {
+		if day.Weekday == time.Sunday && len(day.Courses) > 0 {
+			weekdays = append(weekdays, time.Sunday)
+		}
+	}
+	return weekdays
+}
+
+// gridHours returns the first and last hour of the grid of timetable, which contains
+// the rows of the hours from first until before last.
+func gridHours(timetable *fbnd.Timetable) (first, last int) {
+	first, last = 24, 0
+	for _, day := range timetable.Days {
+		for _, v := range day.Courses {
+			if v.Time.HourStart < first {
+				first = v.Time.HourStart
+			}
+			if v.Time.HourEnd > last {
+				last = v.Time.HourEnd
+			}
+		}
+	}
+	return first, last
+}
+
+// renderGrid returns the lines of a grid with a column for each weekday, see
+// gridWeekdays, and a row for each hour of the timetable, drawn with box-drawing
+// characters. Each course spans the rows of its hours and shows its short name,
+// room and professor, as far as they fit. Parallel courses are shown side by side.
+// Like printTimetable, the current and next courses of today are highlighted,
+// unless there are no lectures today. The first gridHeaderLines lines contain
+// the header, followed by two lines for each hour.
+func renderGrid(timetable *fbnd.Timetable, width int, opts gridOptions) []string {
+	weekdays := gridWeekdays(timetable)
+	courses := make(map[time.Weekday][]fbnd.Course)
+	for _, day := range timetable.Days {
+		courses[day.Weekday] = day.Courses
+	}
+	firstHour, lastHour := gridHours(timetable)
+	if firstHour >= lastHour {
+		return nil
+	}
+
+	lanes := make([][]int, len(weekdays))
+	groups := make([][]gridGroup, len(weekdays))
+	maxLanes := 1
+	for d, weekday := range weekdays {
+		lanes[d], groups[d] = gridLanes(courses[weekday])
+		for _, g := range groups[d] {
+			if g.lanes > maxLanes {
+				maxLanes = g.lanes
+			}
+		}
+	}
+
+	// Each column is separated by a line, parallel courses need a line between them as well.
+	cellWidth := (width-gridLabelWidth-1)/len(weekdays) - 1
+	if min := 2*maxLanes - 1; cellWidth < min {
+		cellWidth = min
+	}
+	if cellWidth < 3 {
+		cellWidth = 3
+	}
+
+	var (
+		now           = opts.now
+		lectureDay    = timetable.IsLectureDay(now)
+		clock         = fbnd.ClockOf(now)
+		todayColor    = color.New(color.FgYellow, color.Bold)
+		currentColor  = color.New(color.FgBlue, color.Bold)
+		nextColor     = color.New(color.FgBlue)
+		selectedColor = color.New(color.ReverseVideo)
+		matchColor    = color.New(color.FgYellow, color.Bold)
+		otherColor    = color.New(color.Faint)
+	)
+
+	g := &gridCanvas{width: gridDayStart(len(weekdays), cellWidth), blocks: make(map[int]*gridBlock)}
+
+	// The header contains the weekdays, which are all abbreviated if one does not fit.
+	abbreviate := len(time.Wednesday.String())+1 > cellWidth
+	header := g.newRow()
+	g.content(header, 1, gridLabelWidth, g.newOwner(), nil, nil)
+	for d, weekday := range weekdays {
+		name := weekday.String()
+		if abbreviate {
+			name = name[:3]
+		}
+		c := color.New(color.FgWhite, color.Bold)
+		if weekday == now.Weekday() {
+			c = color.New(color.FgYellow, color.Bold)
+		}
+		if opts.day != nil && weekday == *opts.day {
+			c.Add(color.Underline)
+		}
+		g.content(header, gridDayStart(d, cellWidth), cellWidth, g.newOwner(), []string{name}, c)
+	}
+
+	// Each course is a block of the same owner in all rows of its hours.
+	owners := make([][]int, len(weekdays))
+	for d, weekday := range weekdays {
+		owners[d] = make([]int, len(courses[weekday]))
+		for i := range owners[d] {
+			owners[d][i] = g.newOwner()
+		}
+	}
+
+	for hour := firstHour; hour < lastHour; hour++ {
+		row := g.newRow()
+		g.content(row, 1, gridLabelWidth, g.newOwner(), []string{fmt.Sprintf("%02d", hour)}, nil)
+
+		for d, weekday := range weekdays {
+			dayStart := gridDayStart(d, cellWidth)
+
+			var group *gridGroup
+			for i := range groups[d] {
+				if groups[d][i].start <= hour && hour < groups[d][i].end {
+					group = &groups[d][i]
+				}
+			}
+			if group == nil {
+				g.content(row, dayStart, cellWidth, g.newOwner(), nil, nil)
+				continue
+			}
+
+			isToday := lectureDay && weekday == now.Weekday()
+			var next map[int]struct{}
+			if isToday {
+				next = nextCourseIndexes(courses[weekday], clock)
+			}
+
+			for lane, x := 0, dayStart; lane < group.lanes; lane++ {
+				laneWidth := (cellWidth - (group.lanes - 1)) / group.lanes
+				if lane < (cellWidth-(group.lanes-1))%group.lanes {
+					laneWidth++
+				}
+				if lane > 0 {
+					row.vertical[x] = true
+					x++
+				}
+
+				course := -1
+				for i, v := range courses[weekday] {
+					if lanes[d][i] == lane && v.Time.HourStart <= hour && hour < v.Time.HourEnd {
+						course = i
+					}
+				}
+				if course < 0 {
+					g.content(row, x, laneWidth, g.newOwner(), nil, nil)
+					x += laneWidth
+					continue
+				}
+
+				v := courses[weekday][course]
+				isSelected := opts.day != nil && weekday == *opts.day && course == opts.course
+				lines := gridCourseLines(v, laneWidth-1)
+				if isSelected {
+					lines[0] = ">" + lines[0]
+				}
+
+				var c *color.Color
+				switch _, isNext := next[course]; {
+				case isSelected:
+					c = selectedColor
+				case opts.matches != nil && opts.matches[v]:
+					c = matchColor
+				case opts.matches != nil:
+					c = otherColor
+				case isToday && v.Time.Contains(now):
+					c = currentColor
+				case isNext:
+					c = nextColor
+				}
+				g.content(row, x, laneWidth, owners[d][course], lines, c)
+				x += laneWidth
+			}
+		}
+	}
+
+	lines := g.render()
+
+	// Like printTimetable, tell why nothing is highlighted today.
+	if !lectureDay && len(courses[now.Weekday()]) > 0 {
+		note := fmt.Sprintf("%s (no lectures)", now.Weekday())
+		if h, ok := holiday.Lookup(now.In(fbnd.Location)); ok {
+			note = fmt.Sprintf("%s (%s, no lectures)", now.Weekday(), h.Name)
+		}
+		lines = append(lines, todayColor.Sprint(note))
+	}
+
+	return lines
+}
+
+// gridDayStart returns the position of the first character of the column of the
+// weekday with the given index.
+func gridDayStart(d, cellWidth int) int {
+	return 1 + gridLabelWidth + 1 + d*(cellWidth+1)
+}
+
+// gridCourseLines returns the lines that describe c, each at most width characters
+// long. The lesson is only added to the name if it fits.
+func gridCourseLines(c fbnd.Course, width int) []string {
+	name := c.NameShort
+	if withLesson := name + " " + string(c.Lesson); utf8.RuneCountInString(withLesson) <= width {
+		name = withLesson
+	}
+	return []string{name, c.Room, c.ProfessorShort}
+}
+
+// gridGroup is a group of courses of a day that overlap directly or through other
+// courses of the group. All rows of its hours are divided into the same lanes.
+type gridGroup struct {
+	start, end int
+	lanes      int
+}
+
+// gridLanes assigns each course to a lane of its group, so that parallel courses are
+// shown side by side. The courses must be sorted by their start hour.
+func gridLanes(courses []fbnd.Course) (lanes []int, groups []gridGroup) {
+	lanes = make([]int, len(courses))
+
+	var laneEnds []int
+	for i, v := range courses {
+		if len(groups) == 0 || v.Time.HourStart >= groups[len(groups)-1].end {
+			groups = append(groups, gridGroup{start: v.Time.HourStart, end: v.Time.HourEnd})
+			laneEnds = laneEnds[:0]
+		}
+		g := &groups[len(groups)-1]
+		if v.Time.HourEnd > g.end {
+			g.end = v.Time.HourEnd
+		}
+
+		lane := 0
+		for lane < len(laneEnds) && laneEnds[lane] > v.Time.HourStart {
+			lane++
+		}
+		if lane == len(laneEnds) {
+			laneEnds = append(laneEnds, 0)
+		}
+		laneEnds[lane] = v.Time.HourEnd
+		lanes[i] = lane
+
+		if len(laneEnds) > g.lanes {
+			g.lanes = len(laneEnds)
+		}
+	}
+
+	return lanes, groups
+}
+
+// gridCanvas draws rows of content separated by lines. A line is only drawn between
+// two characters of content with different owners, so that content with the same
+// owner spans multiple rows.
+type gridCanvas struct {
+	width  int
+	rows   []*gridRow
+	blocks map[int]*gridBlock
+	owners int
+}
+
+// gridRow is a row of the canvas. For each character, either vertical is set and a
+// vertical line is drawn, or owner identifies the content.
+type gridRow struct {
+	owner    []int
+	vertical []bool
+}
+
+// gridBlock is the text of an owner, which starts in the row with index row.
+// The lines of the text are shown in all lines of the owner, including the ones
+// between its rows.
+type gridBlock struct {
+	row   int
+	x     int
+	lines []string
+	color *color.Color
+}
+
+// newOwner returns an owner that differs from all previous ones.
+func (g *gridCanvas) newOwner() int {
+	g.owners++
+	return g.owners
+}
+
+// newRow adds a row whose content is separated by vertical lines at the start and end.
+func (g *gridCanvas) newRow() *gridRow {
+	row := &gridRow{owner: make([]int, g.width), vertical: make([]bool, g.width)}
+	row.vertical[0] = true
+	row.vertical[g.width-1] = true
+	g.rows = append(g.rows, row)
+	return row
+}
+
+// content sets the owner of width characters starting at x and adds the vertical line
+// after them. Owners with the text are registered in their first row.
+func (g *gridCanvas) content(row *gridRow, x, width, owner int, lines []string, c *color.Color) {
+	for i := x; i < x+width; i++ {
+		row.owner[i] = owner
+	}
+	if x+width < g.width {
+		row.vertical[x+width] = true
+	}
+	if _, ok := g.blocks[owner]; !ok && len(lines) > 0 {
+		// Leave a space before the text.
+		for i, v := range lines {
+			lines[i] = truncate(v, width-1)
+		}
+		g.blocks[owner] = &gridBlock{row: len(g.rows) - 1, x: x + 1, lines: lines, color: c}
+	}
+}
+
+// render returns the lines of the canvas including the lines around it.
+func (g *gridCanvas) render() []string {
+	var lines []string
+	for i, row := range g.rows {
+		var above *gridRow
+		if i > 0 {
+			above = g.rows[i-1]
+		}
+		lines = append(lines, g.renderBorder(above, row, 2*i-1))
+		lines = append(lines, g.renderContent(row, 2*i))
+	}
+	return append(lines, g.renderBorder(g.rows[len(g.rows)-1], nil, 2*len(g.rows)-1))
+}
+
+// renderContent renders the content of row, which is line y of the canvas.
+func (g *gridCanvas) renderContent(row *gridRow, y int) string {
+	var sb strings.Builder
+	for x := 0; x < g.width; {
+		if row.vertical[x] {
+			sb.WriteRune('│')
+			x++
+			continue
+		}
+		n := 1
+		for x+n < g.width && !row.vertical[x+n] && row.owner[x+n] == row.owner[x] {
+			n++
+		}
+		g.renderText(&sb, row.owner[x], x, n, y)
+		x += n
+	}
+	return sb.String()
+}
+
+// renderBorder renders the line y between the rows above and below, which are nil
+// at the top and bottom of the canvas.
+func (g *gridCanvas) renderBorder(above, below *gridRow, y int) string {
+	vertical := func(row *gridRow, x int) bool { return row != nil && row.vertical[x] }
+	// spans reports whether the owner of the content above and below x is the same.
+	spans := func(x int) bool {
+		return above != nil && below != nil && !above.vertical[x] && !below.vertical[x] && above.owner[x] == below.owner[x]
+	}
+	horizontal := func(x int) bool {
+		return x >= 0 && x < g.width && !vertical(above, x) && !vertical(below, x) && !spans(x)
+	}
+
+	var sb strings.Builder
+	for x := 0; x < g.width; {
+		if spans(x) {
+			// The text of the owner continues between its rows.
+			n := 1
+			for x+n < g.width && spans(x+n) && above.owner[x+n] == above.owner[x] {
+				n++
+			}
+			g.renderText(&sb, above.owner[x], x, n, y)
+			x += n
+			continue
+		}
+
+		up, down := vertical(above, x), vertical(below, x)
+		if up || down {
+			sb.WriteRune(junction(up, down, horizontal(x-1), horizontal(x+1)))
+		} else {
+			sb.WriteRune('─')
+		}
+		x++
+	}
+	return sb.String()
+}
+
+// renderText writes n characters of the text of owner in line y, starting at x.
+func (g *gridCanvas) renderText(sb *strings.Builder, owner, x, n, y int) {
+	text := []rune(strings.Repeat(" ", n))
+	block := g.blocks[owner]
+	if block != nil {
+		if i := y - 2*block.row; i >= 0 && i < len(block.lines) {
+			for j, r := range []rune(block.lines[i]) {
+				if k := block.x + j - x; k >= 0 && k < n {
+					text[k] = r
+				}
+			}
+		}
+	}
+
+	if block != nil && block.color != nil {
+		sb.WriteString(block.color.Sprint(string(text)))
+	} else {
+		sb.WriteString(string(text))
+	}
+}
+
+// junction returns the box-drawing character that connects lines in the given directions.
+func junction(up, down, left, right bool) rune {
+	switch {
+	case up && down && left && right:
+		return '┼'
+	case up && down && left:
+		return '┤'
+	case up && down && right:
+		return '├'
+	case up && down:
+		return '│'
+	case down && left && right:
+		return '┬'
+	case down && left:
+		return '┐'
+	case down && right:
+		return '┌'
+	case up && left && right:
+		return '┴'
+	case up && left:
+		return '┘'
+	case up && right:
+		return '└'
+	case left || right:
+		return '─'
+	default:
+		return ' '
+	}
+}
+
+// truncate returns s shortened to at most width characters.
+func truncate(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	if utf8.RuneCountInString(s) <= width {
+		return s
+	}
+	return string([]rune(s)[:width-1]) + "…"
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+	"github.com/n9v9/fbnd"
+)
+
+func TestGridLanes(t *testing.T) {
+	courses := []fbnd.Course{
+		{NameShort: "MA1", Time: fbnd.FB03Slots.Time(time.Monday, 8, 10)},
+		{NameShort: "PR1-A", Time: fbnd.FB03Slots.Time(time.Monday, 10, 12)},
+		{NameShort: "PR1-B", Time: fbnd.FB03Slots.Time(time.Monday, 10, 13)},
+		{NameShort: "PR1-C", Time: fbnd.FB03Slots.Time(time.Monday, 12, 14)},
+		{NameShort: "DB", Time: fbnd.FB03Slots.Time(time.Monday, 14, 16)},
+	}
+
+	lanes, groups := gridLanes(courses)
+
+	if want := []int{0, 0, 1, 0, 0}; !reflect.DeepEqual(lanes, want) {
+		t.Fatalf("want %v, got %v", want, lanes)
+	}
+	want := []gridGroup{{start: 8, end: 10, lanes: 1}, {start: 10, end: 14, lanes: 2}, {start: 14, end: 16, lanes: 1}}
+	if !reflect.DeepEqual(groups, want) {
+		t.Fatalf("want %v, got %v", want, groups)
+	}
+}
+
+func TestRenderGrid(t *testing.T) {
+	defer func(noColor bool) { color.NoColor = noColor }(color.NoColor)
+	color.NoColor = true
+
+	timetable := &fbnd.Timetable{Days: []fbnd.TimetableDay{
+		{Weekday: time.Monday, Courses: []fbnd.Course{
+			{NameShort: "MA1", ProfessorShort: "Mül", Room: "B 1.10", Lesson: fbnd.Lecture, Time: fbnd.FB03Slots.Time(time.Monday, 8, 10)},
+			{NameShort: "PR1-A", ProfessorShort: "Sch", Room: "Z 2.10", Lesson: fbnd.Exercise, Time: fbnd.FB03Slots.Time(time.Monday, 10, 12)},
+			{NameShort: "PR1-B", ProfessorShort: "Sch", Room: "Z 2.11", Lesson: fbnd.Exercise, Time: fbnd.FB03Slots.Time(time.Monday, 10, 11)},
+		}},
+		{Weekday: time.Wednesday, Courses: []fbnd.Course{
+			{NameShort: "Betriebssysteme", ProfessorShort: "Sch", Room: "Z 2.10", Lesson: fbnd.Lecture, Time: fbnd.FB03Slots.Time(time.Wednesday, 9, 10)},
+		}},
+	}}
+	// A Monday during the lecture period.
+	now := time.Date(2022, 10, 10, 9, 0, 0, 0, fbnd.Location)
+
+	want := []string{
+		"┌────┬─────────┬─────────┬─────────┬─────────┬─────────┬─────────┐",
+		"│    │ Mon     │ Tue     │ Wed     │ Thu     │ Fri     │ Sat     │",
+		"├────┼─────────┼─────────┼─────────┼─────────┼─────────┼─────────┤",
+		"│ 08 │ MA1 V   │         │         │         │         │         │",
+		"├────┤ B 1.10  ├─────────┼─────────┼─────────┼─────────┼─────────┤",
+		"│ 09 │ Mül     │         │ Betrieb…│         │         │         │",
+		"├────┼────┬────┼─────────┼─────────┼─────────┼─────────┼─────────┤",
+		"│ 10 │ PR…│ PR…│         │         │         │         │         │",
+		"├────┤ Z …├────┼─────────┼─────────┼─────────┼─────────┼─────────┤",
+		"│ 11 │ Sch│    │         │         │         │         │         │",
+		"└────┴────┴────┴─────────┴─────────┴─────────┴─────────┴─────────┘",
+	}
+
+	got := renderGrid(timetable, 66, gridOptions{now: now, course: -1})
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("want\n%s\ngot\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+	for i, line := range got {
+		if n := utf8.RuneCountInString(line); n > 66 {
+			t.Fatalf("want line %d to fit into 66 columns, got %d", i, n)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/n9v9/fbnd/ical"
+	"github.com/spf13/cobra"
+)
+
+func cmdICSServer() *cobra.Command {
+	var (
+		addr string
+		ttl  time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "ics-server",
+		Short: "Serve the timetables of degree programs as iCalendar feeds",
+		Long: `Serve the timetables of degree programs as iCalendar feeds
+
+Calendar applications can subscribe to the feed of a degree program at
+
+  GET /ics/<ID>.ics
+
+and receive changes of the timetable automatically. The courses of a feed can be
+filtered with the following query parameters, which can be given multiple times or
+contain values separated by commas:
+
+  lesson   Only include these lessons, like V or Lecture
+  exclude  Leave out these courses, like EN
+  group    Only include this group of courses split into groups, like A,
+           or this group of one course, like PR1-B
+
+For example: /ics/BI3.ics?lesson=V,U&exclude=EN&group=A&group=PR1-B`,
+		Args:   cobra.NoArgs,
+		PreRun: initClient,
+		Run: func(cmd *cobra.Command, _ []string) {
+			if err := runICSServer(cmd.Context(), addr, ttl); err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&addr, "addr", "localhost:8080", "Address to listen on")
+	cmd.Flags().DurationVar(&ttl, "ttl", 10*time.Minute, "Duration for which timetables are kept in memory")
+
+	return cmd
+}
+
+func runICSServer(ctx context.Context, addr string, ttl time.Duration) error {
+	logger := log.New(os.Stderr, "", log.LstdFlags)
+
+	mux := http.NewServeMux()
+	mux.Handle("/ics/", ical.NewHandler(client, ttl, logger))
+
+	return listenAndServe(ctx, addr, mux, logger)
+}
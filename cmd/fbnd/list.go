@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -24,9 +25,10 @@ func cmdList() *cobra.Command {
 			}
 			return cobra.NoArgs(cmd, args)
 		},
-		Run: func(_ *cobra.Command, _ []string) {
-			if err := runList(); err != nil {
-				fmt.Fprintln(os.Stderr, err)
+		PreRun: initClient,
+		Run: func(cmd *cobra.Command, _ []string) {
+			if err := runList(cmd.Context()); err != nil {
+				printError(err)
 				os.Exit(1)
 			}
 		},
@@ -38,7 +40,7 @@ func cmdList() *cobra.Command {
 	return cmd
 }
 
-func runList() error {
+func runList(ctx context.Context) error {
 	if !summer && !winter {
 		// By default we want to display all degree programs.
 		// So if they were not passed in we can invert them for easier handling.
@@ -53,7 +55,7 @@ func runList() error {
 	)
 
 	fetch := func(cycle fbnd.SemesterCycle) {
-		programs, err := fbnd.DegreePrograms(cycle)
+		programs, err := client.DegreePrograms(ctx, cycle)
 		if err != nil {
 			var semester string
 			if cycle == fbnd.Summer {
@@ -61,7 +63,8 @@ func runList() error {
 			} else {
 				semester = "winter"
 			}
-			errCh <- fmt.Errorf("could not fetch degree programs for the %v semester: %v", semester, err)
+			errCh <- fmt.Errorf("could not fetch degree programs for the %v semester: %w", semester, err)
+			return
 		}
 		programsCh <- programs
 	}
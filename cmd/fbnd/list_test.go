@@ -0,0 +1,41 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/n9v9/fbnd"
+)
+
+func TestRunListStructureChanged(t *testing.T) {
+	// The label of the semester does not contain its year.
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`<input id="inlineWintersemester" checked><label for="inlineWintersemester">Winter 22</label>`))
+	}))
+	defer upstream.Close()
+
+	defer func(c *fbnd.Client, s, w bool) { client, summer, winter = c, s, w }(client, summer, winter)
+	client = fbnd.NewClient(fbnd.WithBaseURL(upstream.URL), fbnd.WithHTTPClient(upstream.Client()))
+	summer, winter = false, true
+
+	err := runList(context.Background())
+	if err == nil {
+		t.Fatal("want error for a broken page, got nil")
+	}
+
+	var buf bytes.Buffer
+	fprintError(&buf, err)
+	for _, want := range []string{
+		"could not fetch degree programs for the winter semester",
+		"The structure of the timetable website seems to have changed",
+		"Field:    semester year",
+	} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("want output to contain %q, got\n%s", want, buf.String())
+		}
+	}
+}
@@ -1,13 +1,33 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
-	if err := cmdRoot().Execute(); err != nil {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := cmdRoot().ExecuteContext(ctx); err != nil {
 		fmt.Println(err)
-		os.Exit(1)
+		stop()
+		os.Exit(exitCode(err))
+	}
+}
+
+// errConflicts is returned when overlapping courses were found. It is signaled
+// with its own exit code, so that scripts can tell it apart from failures.
+var errConflicts = errors.New("found conflicts")
+
+// exitCode returns the status with which the program exits because of err.
+func exitCode(err error) int {
+	if errors.Is(err, errConflicts) {
+		return 2
 	}
+	return 1
 }
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/n9v9/fbnd"
+	"github.com/spf13/cobra"
+)
+
+func cmdOptimize() *cobra.Command {
+	var (
+		rank []string
+		top  int
+	)
+
+	cmd := &cobra.Command{
+		Use:   "optimize <ID>:<module>...",
+		Short: "Find the best combinations of exercise and internship groups",
+		Long: `Find the best combinations of exercise and internship groups
+
+Each argument is the ID of a degree program and the short name of a module of its
+timetable, without the group, separated by a colon. For example, to attend databases
+and software engineering of BI3 as well as machine learning of MI1:
+
+  fbnd optimize BI3:DB BI3:SE MI1:ML
+
+All lectures and other courses of the modules are attended. Exercises and internships
+that are offered in several parallel groups, like DB-A and DB-B, are attended in one
+of them. All combinations of groups in which no courses overlap are ranked and the
+best ones are shown. The criteria of --rank are applied in order:
+
+  days   Fewest weekdays with courses.
+  gaps   Fewest free hours between the courses of a day.
+  start  Latest start of the first course of the week.`,
+		Args:   cobra.MinimumNArgs(1),
+		PreRun: initClient,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runOptimize(cmd.Context(), args, rank, top); err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().StringSliceVar(&rank, "rank", []string{"days", "gaps", "start"}, "Criteria by which the combinations are ranked")
+	cmd.Flags().IntVar(&top, "top", 3, "Number of combinations to show")
+
+	return cmd
+}
+
+func runOptimize(ctx context.Context, modules, rank []string, top int) error {
+	var criteria []fbnd.Criterion
+	for _, v := range rank {
+		c, err := fbnd.ParseCriterion(strings.ToLower(strings.TrimSpace(v)))
+		if err != nil {
+			return err
+		}
+		criteria = append(criteria, c)
+	}
+	if top < 1 {
+		return fmt.Errorf("invalid number of combinations %d, expected at least 1", top)
+	}
+
+	courses, err := moduleCourses(ctx, client, modules)
+	if err != nil {
+		return err
+	}
+
+	schedules := fbnd.Optimize(courses, criteria, top)
+	if schedules == nil {
+		schedules = []fbnd.Schedule{}
+	}
+
+	if outputJSON {
+		return json.NewEncoder(os.Stdout).Encode(schedules)
+	}
+
+	if len(schedules) == 0 {
+		return errors.New("could find no combination of groups in which no courses overlap, see the conflicts command")
+	}
+
+	printlnHeader := color.New(color.FgWhite, color.Bold).PrintlnFunc()
+	for i, v := range schedules {
+		if i > 0 {
+			fmt.Println()
+		}
+		printlnHeader(fmt.Sprintf("Combination %d: %d days, %d hours of gaps, first course at %d", i+1, v.Days, v.Gaps, v.Start))
+		fmt.Println()
+		printTimetable(v.Timetable())
+	}
+
+	return nil
+}
+
+// moduleCourses returns the courses of the modules, which are given as <ID>:<module>.
+// A course is part of a module if its name without the group equals the module, ignoring case.
+func moduleCourses(ctx context.Context, c *fbnd.Client, modules []string) ([]fbnd.Course, error) {
+	var courses []fbnd.Course
+	timetables := make(map[fbnd.ID]*fbnd.Timetable)
+
+	for _, v := range modules {
+		id, module, ok := strings.Cut(v, ":")
+		if !ok || id == "" || module == "" {
+			return nil, fmt.Errorf("invalid module %q, expected <ID>:<module> like BI3:DB", v)
+		}
+
+		programID := fbnd.ID(strings.ToUpper(id))
+		timetable, ok := timetables[programID]
+		if !ok {
+			var err error
+			if timetable, err = c.TimetableForDegreeProgram(ctx, programID); err != nil {
+				return nil, err
+			}
+			timetables[programID] = timetable
+		}
+
+		var found bool
+		for _, day := range timetable.Days {
+			for _, course := range day.Courses {
+				if name, _ := course.Group(); strings.EqualFold(name, module) {
+					courses = append(courses, course)
+					found = true
+				}
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("could find no module %s in the timetable of %s", module, programID)
+		}
+	}
+
+	return courses, nil
+}
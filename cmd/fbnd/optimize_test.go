@@ -0,0 +1,60 @@
+package main
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/n9v9/fbnd"
+	"github.com/n9v9/fbnd/fbndtest"
+)
+
+func TestModuleCourses(t *testing.T) {
+	db := fbnd.Course{
+		NameLong: "Datenbanken", NameShort: "DB", ProfessorLong: "Prof. Dr. Müller", ProfessorShort: "Mül",
+		Room: "B 1.10", Lesson: fbnd.Lecture, Time: fbnd.FB03Slots.Time(time.Monday, 8, 10),
+	}
+	dbA := fbnd.Course{
+		NameLong: "Datenbanken", NameShort: "DB-A", ProfessorLong: "Prof. Dr. Müller", ProfessorShort: "Mül",
+		Room: "B 1.10", Lesson: fbnd.Exercise, Time: fbnd.FB03Slots.Time(time.Monday, 10, 12),
+	}
+	dbs := fbnd.Course{
+		NameLong: "Datenbanksysteme", NameShort: "DBS", ProfessorLong: "Prof. Dr. Schmidt", ProfessorShort: "Sch",
+		Room: "Z 2.10", Lesson: fbnd.Lecture, Time: fbnd.FB03Slots.Time(time.Tuesday, 8, 10),
+	}
+	upstream := fbndtest.NewServer(fbndtest.SamplePrograms(), map[fbnd.ID][]fbnd.Course{"BI1": {db, dbA, dbs}})
+	defer upstream.Close()
+
+	type testCase struct {
+		name    string
+		modules []string
+		want    []fbnd.Course
+		wantErr bool
+	}
+
+	tests := []testCase{
+		{name: "module with groups", modules: []string{"bi1:db"}, want: []fbnd.Course{db, dbA}},
+		{name: "multiple modules", modules: []string{"BI1:DB", "BI1:DBS"}, want: []fbnd.Course{db, dbA, dbs}},
+		{name: "unknown module", modules: []string{"BI1:SE"}, wantErr: true},
+		{name: "missing program", modules: []string{"DB"}, wantErr: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, err := moduleCourses(context.Background(), upstream.Client(), test.modules)
+			if test.wantErr {
+				if err == nil {
+					t.Fatal("want error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("want %v, got %v", test.want, got)
+			}
+		})
+	}
+}
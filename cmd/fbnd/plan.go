@@ -0,0 +1,376 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/n9v9/fbnd"
+	"github.com/spf13/cobra"
+)
+
+func cmdPlan() *cobra.Command {
+	var path string
+
+	cmd := &cobra.Command{
+		Use:   "plan",
+		Short: "Build a personal timetable from the courses of several degree programs",
+		Long: `Build a personal timetable from the courses of several degree programs
+
+Courses are added to and removed from the plan, which is stored in a local file.
+The show command displays the courses of the plan with the current data of their
+timetables, like the time command does.`,
+	}
+
+	cmd.PersistentFlags().StringVar(&path, "file", "", "File in which the plan is stored (default \"<config dir>/fbnd/plan.json\")")
+
+	resolvePath := func() (string, error) {
+		if path != "" {
+			return path, nil
+		}
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return "", fmt.Errorf("could not determine config directory: %w", err)
+		}
+		return filepath.Join(dir, "fbnd", "plan.json"), nil
+	}
+
+	cmd.AddCommand(cmdPlanAdd(resolvePath))
+	cmd.AddCommand(cmdPlanRemove(resolvePath))
+	cmd.AddCommand(cmdPlanShow(resolvePath))
+
+	return cmd
+}
+
+// courseSelectorFlags registers the flags that narrow down the courses selected by
+// the arguments <ID> <course> and returns a function that builds the selector.
+func courseSelectorFlags(cmd *cobra.Command) func(args []string) (courseSelector, error) {
+	var lesson, day string
+	var hour int
+
+	cmd.Flags().StringVar(&lesson, "lesson", "", "Only select courses of this lesson, like V or Lecture")
+	cmd.Flags().StringVar(&day, "day", "", "Only select courses on this weekday, like Tue")
+	cmd.Flags().IntVar(&hour, "hour", 0, "Only select courses that start at this hour of the timetable, like 10")
+
+	return func(args []string) (courseSelector, error) {
+		s := courseSelector{id: fbnd.ID(strings.ToUpper(args[0])), nameShort: args[1], hour: hour}
+		if lesson != "" {
+			l, err := fbnd.ParseLesson(lesson)
+			if err != nil {
+				return courseSelector{}, err
+			}
+			s.lesson = l
+		}
+		if day != "" {
+			weekday, err := parseWeekday(day)
+			if err != nil {
+				return courseSelector{}, err
+			}
+			s.weekday = &weekday
+		}
+		return s, nil
+	}
+}
+
+func cmdPlanAdd(resolvePath func() (string, error)) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add <ID> <course>",
+		Short: "Add courses of a degree program to the plan",
+		Long: `Add courses of a degree program to the plan
+
+This command expects the ID of the degree program and the short name of the course,
+as shown by the time command. All courses with this name are added, unless the flags
+narrow them down, for example to only add the exercise on Tuesday at 10:
+
+  fbnd plan add BI3 DB-A --lesson U --day Tue --hour 10`,
+		Args:   cobra.ExactArgs(2),
+		PreRun: initClient,
+	}
+
+	selector := courseSelectorFlags(cmd)
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		if err := runPlanAdd(cmd.Context(), resolvePath, selector, args); err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+	}
+
+	return cmd
+}
+
+func runPlanAdd(ctx context.Context, resolvePath func() (string, error), selector func([]string) (courseSelector, error), args []string) error {
+	s, err := selector(args)
+	if err != nil {
+		return err
+	}
+	path, err := resolvePath()
+	if err != nil {
+		return err
+	}
+	entries, err := readPlan(path)
+	if err != nil {
+		return err
+	}
+
+	timetable, err := client.TimetableForDegreeProgram(ctx, s.id)
+	if err != nil {
+		return err
+	}
+
+	var found bool
+	for _, day := range timetable.Days {
+		for _, course := range day.Courses {
+			if !s.selects(course) {
+				continue
+			}
+			found = true
+
+			entry := newPlanEntry(s.id, course)
+			if containsEntry(entries, entry) {
+				fmt.Printf("Already planned %s\n", entry)
+				continue
+			}
+			entries = append(entries, entry)
+			fmt.Printf("Added %s\n", entry)
+		}
+	}
+	if !found {
+		return fmt.Errorf("could find no course %s in the timetable of %s", s.nameShort, s.id)
+	}
+
+	return writePlan(path, entries)
+}
+
+func cmdPlanRemove(resolvePath func() (string, error)) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove <ID> <course>",
+		Short: "Remove courses of a degree program from the plan",
+		Long: `Remove courses of a degree program from the plan
+
+This command expects the ID of the degree program and the short name of the course.
+All planned courses with this name are removed, unless the flags narrow them down.`,
+		Args: cobra.ExactArgs(2),
+	}
+
+	selector := courseSelectorFlags(cmd)
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		if err := runPlanRemove(resolvePath, selector, args); err != nil {
+			printError(err)
+			os.Exit(1)
+		}
+	}
+
+	return cmd
+}
+
+func runPlanRemove(resolvePath func() (string, error), selector func([]string) (courseSelector, error), args []string) error {
+	s, err := selector(args)
+	if err != nil {
+		return err
+	}
+	path, err := resolvePath()
+	if err != nil {
+		return err
+	}
+	entries, err := readPlan(path)
+	if err != nil {
+		return err
+	}
+
+	var kept []planEntry
+	for _, v := range entries {
+		if v.Program == s.id && s.selects(v.course()) {
+			fmt.Printf("Removed %s\n", v)
+			continue
+		}
+		kept = append(kept, v)
+	}
+	if len(kept) == len(entries) {
+		return fmt.Errorf("could find no planned course %s of %s", s.nameShort, s.id)
+	}
+
+	return writePlan(path, kept)
+}
+
+func cmdPlanShow(resolvePath func() (string, error)) *cobra.Command {
+	return &cobra.Command{
+		Use:    "show",
+		Short:  "Display the courses of the plan",
+		Args:   cobra.NoArgs,
+		PreRun: initClient,
+		Run: func(cmd *cobra.Command, _ []string) {
+			if err := runPlanShow(cmd.Context(), resolvePath); err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+		},
+	}
+}
+
+func runPlanShow(ctx context.Context, resolvePath func() (string, error)) error {
+	path, err := resolvePath()
+	if err != nil {
+		return err
+	}
+	entries, err := readPlan(path)
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return errors.New("the plan is empty, add courses with the command plan add")
+	}
+
+	timetable, missing, err := resolvePlan(ctx, client, entries)
+	if err != nil {
+		return err
+	}
+	for _, v := range missing {
+		fmt.Fprintf(os.Stderr, "The timetable of %s does not contain the planned %s anymore\n", v.Program, v)
+	}
+
+	if outputJSON {
+		return json.NewEncoder(os.Stdout).Encode(timetable)
+	}
+
+	if len(timetable.Days) > 0 {
+		printTimetable(timetable)
+	}
+	return nil
+}
+
+// resolvePlan fetches the current timetables of the planned courses and returns
+// a Timetable with all of them, as well as the entries that could not be found.
+func resolvePlan(ctx context.Context, c *fbnd.Client, entries []planEntry) (*fbnd.Timetable, []planEntry, error) {
+	var (
+		ids        []fbnd.ID
+		byProgram  = make(map[fbnd.ID][]planEntry)
+		timetables []*fbnd.Timetable
+		missing    []planEntry
+	)
+	for _, v := range entries {
+		if _, ok := byProgram[v.Program]; !ok {
+			ids = append(ids, v.Program)
+		}
+		byProgram[v.Program] = append(byProgram[v.Program], v)
+	}
+
+	for _, id := range ids {
+		timetable, err := c.TimetableForDegreeProgram(ctx, id)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		found := make(map[planEntry]bool)
+		planned := fbnd.Merge([]*fbnd.Timetable{timetable}, func(course fbnd.Course) bool {
+			entry := newPlanEntry(id, course)
+			if containsEntry(byProgram[id], entry) {
+				found[entry] = true
+				return true
+			}
+			return false
+		})
+		timetables = append(timetables, planned)
+
+		for _, v := range byProgram[id] {
+			if !found[v] {
+				missing = append(missing, v)
+			}
+		}
+	}
+
+	return fbnd.Merge(timetables, func(fbnd.Course) bool { return true }), missing, nil
+}
+
+// courseSelector selects the courses of a degree program by their short name and
+// optionally by their lesson, weekday and start hour.
+type courseSelector struct {
+	id        fbnd.ID
+	nameShort string
+	lesson    fbnd.Lesson
+	weekday   *time.Weekday
+	hour      int
+}
+
+func (s courseSelector) selects(c fbnd.Course) bool {
+	return strings.EqualFold(c.NameShort, s.nameShort) &&
+		(s.lesson == "" || c.Lesson == s.lesson) &&
+		(s.weekday == nil || c.Time.Weekday == *s.weekday) &&
+		(s.hour == 0 || c.Time.HourStart == s.hour)
+}
+
+// planEntry identifies a planned course across updates of its timetable,
+// so that changes of its room or professor are shown.
+type planEntry struct {
+	Program   fbnd.ID      `json:"program"`
+	NameShort string       `json:"nameShort"`
+	Lesson    fbnd.Lesson  `json:"lesson"`
+	Weekday   time.Weekday `json:"weekday"`
+	Hour      int          `json:"hour"`
+}
+
+func newPlanEntry(id fbnd.ID, c fbnd.Course) planEntry {
+	return planEntry{
+		Program:   id,
+		NameShort: c.NameShort,
+		Lesson:    c.Lesson,
+		Weekday:   c.Time.Weekday,
+		Hour:      c.Time.HourStart,
+	}
+}
+
+// course returns a Course with the fields of e, to be matched by a courseSelector.
+func (e planEntry) course() fbnd.Course {
+	return fbnd.Course{
+		NameShort: e.NameShort,
+		Lesson:    e.Lesson,
+		Time:      fbnd.Time{Weekday: e.Weekday, HourStart: e.Hour},
+	}
+}
+
+func (e planEntry) String() string {
+	return fmt.Sprintf("%s %s (%s) on %s at %d", e.Program, e.NameShort, e.Lesson, e.Weekday, e.Hour)
+}
+
+func containsEntry(entries []planEntry, entry planEntry) bool {
+	for _, v := range entries {
+		if v == entry {
+			return true
+		}
+	}
+	return false
+}
+
+// readPlan reads the entries of the plan stored at path. If there is no such file,
+// the plan is empty.
+func readPlan(path string) ([]planEntry, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []planEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("could not parse plan in %s: %w", path, err)
+	}
+	return entries, nil
+}
+
+func writePlan(path string, entries []planEntry) error {
+	if entries == nil {
+		entries = []planEntry{}
+	}
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/n9v9/fbnd"
+	"github.com/n9v9/fbnd/fbndtest"
+)
+
+func TestResolvePlan(t *testing.T) {
+	ma1, pr1 := fbndtest.SampleCourses()[0], fbndtest.SampleCourses()[2]
+	ml := fbnd.Course{
+		NameLong: "Maschinelles Lernen", NameShort: "ML", ProfessorLong: "Prof. Dr. Schmidt", ProfessorShort: "Sch",
+		Room: "Z 3.10", Lesson: fbnd.Exercise, Time: fbnd.FB03Slots.Time(time.Monday, 12, 14),
+	}
+	upstream := fbndtest.NewServer(fbndtest.SamplePrograms(), map[fbnd.ID][]fbnd.Course{
+		"BI1": {ma1, pr1},
+		"MI1": {ml},
+	})
+	defer upstream.Close()
+
+	entries := []planEntry{newPlanEntry("BI1", ma1), newPlanEntry("MI1", ml)}
+
+	// The room changed since the course was planned, which is shown.
+	ml.Room = "Z 4.10"
+	upstream.SetTimetable("MI1", []fbnd.Course{ml})
+
+	timetable, missing, err := resolvePlan(context.Background(), upstream.Client(), entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(missing) != 0 {
+		t.Fatalf("want no missing entries, got %v", missing)
+	}
+	want := []fbnd.TimetableDay{{Weekday: time.Monday, Courses: []fbnd.Course{ma1, ml}}}
+	if !reflect.DeepEqual(timetable.Days, want) {
+		t.Fatalf("want %v, got %v", want, timetable.Days)
+	}
+
+	// Moving the course to another time means it is not the planned one anymore.
+	ml.Time = fbnd.FB03Slots.Time(time.Tuesday, 12, 14)
+	upstream.SetTimetable("MI1", []fbnd.Course{ml})
+
+	timetable, missing, err = resolvePlan(context.Background(), upstream.Client(), entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(missing, entries[1:]) {
+		t.Fatalf("want %v, got %v", entries[1:], missing)
+	}
+	want = []fbnd.TimetableDay{{Weekday: time.Monday, Courses: []fbnd.Course{ma1}}}
+	if !reflect.DeepEqual(timetable.Days, want) {
+		t.Fatalf("want %v, got %v", want, timetable.Days)
+	}
+}
+
+func TestReadWritePlan(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fbnd", "plan.json")
+
+	entries, err := readPlan(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("want empty plan, got %v", entries)
+	}
+
+	want := []planEntry{{Program: "BI1", NameShort: "MA1", Lesson: fbnd.Lecture, Weekday: time.Monday, Hour: 8}}
+	if err := writePlan(path, want); err != nil {
+		t.Fatal(err)
+	}
+	if entries, err = readPlan(path); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(entries, want) {
+		t.Fatalf("want %v, got %v", want, entries)
+	}
+}
+
+func TestCourseSelector(t *testing.T) {
+	course := fbnd.Course{NameShort: "DB-A", Lesson: fbnd.Exercise, Time: fbnd.FB03Slots.Time(time.Tuesday, 10, 12)}
+	tuesday, monday := time.Tuesday, time.Monday
+
+	type testCase struct {
+		name     string
+		selector courseSelector
+		want     bool
+	}
+
+	tests := []testCase{
+		{name: "name only", selector: courseSelector{nameShort: "db-a"}, want: true},
+		{name: "other name", selector: courseSelector{nameShort: "DB-B"}, want: false},
+		{name: "all fields", selector: courseSelector{nameShort: "DB-A", lesson: fbnd.Exercise, weekday: &tuesday, hour: 10}, want: true},
+		{name: "other lesson", selector: courseSelector{nameShort: "DB-A", lesson: fbnd.Lecture}, want: false},
+		{name: "other weekday", selector: courseSelector{nameShort: "DB-A", weekday: &monday}, want: false},
+		{name: "other hour", selector: courseSelector{nameShort: "DB-A", hour: 12}, want: false},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.selector.selects(course); got != test.want {
+				t.Fatalf("want %v, got %v", test.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func cmdProf() *cobra.Command {
+	return &cobra.Command{
+		Use:   "prof <name>",
+		Short: "Display all courses held by a specific professor",
+		Long: `Display all courses held by a specific professor
+
+The courses of all degree programs of both semesters are shown, like the time command
+shows the courses of a single degree program. Courses that are part of multiple degree
+programs are only shown once.
+
+This command expects the short name of the professor as shown by the time command,
+like Mül, or a part of the full name, like Müller. Case is ignored.`,
+		Args:   cobra.MinimumNArgs(1),
+		PreRun: initClient,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runProf(cmd.Context(), strings.Join(args, " ")); err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+		},
+	}
+}
+
+func runProf(ctx context.Context, name string) error {
+	timetable, err := client.ProfessorTimetable(ctx, name)
+	if err != nil {
+		return err
+	}
+	if len(timetable.Days) == 0 {
+		return fmt.Errorf("could find no courses held by %s", name)
+	}
+
+	if outputJSON {
+		return json.NewEncoder(os.Stdout).Encode(timetable.Days)
+	}
+
+	printTimetable(timetable)
+	return nil
+}
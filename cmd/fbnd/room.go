@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func cmdRoom() *cobra.Command {
+	return &cobra.Command{
+		Use:   "room <name>",
+		Short: "Display all courses that take place in a specific room",
+		Long: `Display all courses that take place in a specific room
+
+The courses of all degree programs of both semesters are shown, like the time command
+shows the courses of a single degree program. Courses that are part of multiple degree
+programs are only shown once.
+
+This command expects the name of the room, like "Z 2.10". Case and spaces are ignored,
+so z2.10 works as well.`,
+		Args:   cobra.MinimumNArgs(1),
+		PreRun: initClient,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runRoom(cmd.Context(), strings.Join(args, " ")); err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+		},
+	}
+}
+
+func runRoom(ctx context.Context, room string) error {
+	timetable, err := client.RoomTimetable(ctx, room)
+	if err != nil {
+		return err
+	}
+	if len(timetable.Days) == 0 {
+		return fmt.Errorf("could find no courses in room %s", room)
+	}
+
+	if outputJSON {
+		return json.NewEncoder(os.Stdout).Encode(timetable)
+	}
+
+	printTimetable(timetable)
+	return nil
+}
@@ -1,18 +1,34 @@
 package main
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 	"os"
 	"runtime/debug"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
+	"github.com/n9v9/fbnd"
 	"github.com/spf13/cobra"
 )
 
 // Global flags that are set for all commands.
 var (
 	outputJSON = false
+	apiURL     = ""
+	noCache    = false
+	refresh    = false
+	offline    = false
+	cacheTTL   = time.Hour
+	retries    = 2
+	rateLimit  = 100 * time.Millisecond
+	timeout    = 30 * time.Second
+	calendars  = ""
+	slots      = ""
+	client     = fbnd.DefaultClient
 )
 
 func cmdRoot() *cobra.Command {
@@ -33,13 +49,112 @@ func cmdRoot() *cobra.Command {
 
 	cmd.PersistentFlags().BoolVar(&outputJSON, "json", false, "Enable printing results in JSON format")
 	cmd.PersistentFlags().Bool("no-color", false, "Disable colorized output")
+	cmd.PersistentFlags().StringVar(&apiURL, "api", "", "Base URL of a JSON API served by fbnd serve to use instead of scraping the website")
+	cmd.PersistentFlags().BoolVar(&noCache, "no-cache", false, "Disable caching of responses")
+	cmd.PersistentFlags().BoolVar(&refresh, "refresh", false, "Ignore cached responses and fetch fresh data")
+	cmd.PersistentFlags().BoolVar(&offline, "offline", false, "Only use cached responses, regardless of their age")
+	cmd.PersistentFlags().DurationVar(&cacheTTL, "cache-ttl", cacheTTL, "Duration for which cached responses are used without fetching fresh data")
+	cmd.PersistentFlags().IntVar(&retries, "retries", retries, "Number of times a request is retried on server errors and timeouts")
+	cmd.PersistentFlags().DurationVar(&rateLimit, "rate-limit", rateLimit, "Minimum duration between two requests to the server")
+	cmd.PersistentFlags().DurationVar(&timeout, "timeout", timeout, "Timeout of a single request to the server")
+	cmd.PersistentFlags().StringVar(&calendars, "calendars", "", "JSON file with the lecture periods and breaks of semesters (default \"<config dir>/fbnd/calendars.json\")")
+	cmd.PersistentFlags().StringVar(&slots, "slots", "", "JSON file with the clock times of the columns of the timetable (default \"<config dir>/fbnd/slots.json\")")
 
 	cmd.AddCommand(cmdTime())
 	cmd.AddCommand(cmdList())
+	cmd.AddCommand(cmdExport())
+	cmd.AddCommand(cmdServe())
+	cmd.AddCommand(cmdICSServer())
+	cmd.AddCommand(cmdDiff())
+	cmd.AddCommand(cmdWatch())
+	cmd.AddCommand(cmdRoom())
+	cmd.AddCommand(cmdFreeRooms())
+	cmd.AddCommand(cmdProf())
+	cmd.AddCommand(cmdSearch())
+	cmd.AddCommand(cmdPlan())
+	cmd.AddCommand(cmdConflicts())
+	cmd.AddCommand(cmdOptimize())
+	cmd.AddCommand(cmdTUI())
 
 	return cmd
 }
 
+// initClient sets client to a Client configured by the global flags.
+// Commands that fetch data use it as their PreRun, so that the files of the calendars
+// and slots are only read, and their errors only reported, when a client is needed.
+func initClient(*cobra.Command, []string) {
+	mode, err := cacheMode()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	client, err = newClient(mode)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// newClient returns a Client configured by the global flags. Unless caching is
+// disabled, its cache uses the given mode, see cacheMode.
+func newClient(mode fbnd.CacheMode) (*fbnd.Client, error) {
+	retryPolicy := fbnd.DefaultRetryPolicy
+	retryPolicy.MaxAttempts = retries + 1
+
+	opts := []fbnd.Option{
+		fbnd.WithHTTPClient(&http.Client{Timeout: timeout}),
+		fbnd.WithRetry(retryPolicy),
+	}
+	if rateLimit > 0 {
+		opts = append(opts, fbnd.WithRateLimit(rateLimit))
+	}
+
+	if apiURL != "" {
+		opts = append(opts, fbnd.WithBaseURL(apiURL), fbnd.WithJSONAPI())
+	}
+
+	calendarOpts, err := calendarOptions(calendars)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, calendarOpts...)
+
+	slotOpts, err := slotOptions(slots)
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, slotOpts...)
+
+	if !noCache {
+		dir, err := fbnd.DefaultCacheDir()
+		if err != nil {
+			return nil, fmt.Errorf("could not determine cache directory: %w", err)
+		}
+		opts = append(opts, fbnd.WithCache(fbnd.NewCache(dir, cacheTTL, mode)))
+	}
+
+	return fbnd.NewClient(opts...), nil
+}
+
+// cacheMode returns the mode of the cache selected by the global flags.
+func cacheMode() (fbnd.CacheMode, error) {
+	if refresh && offline {
+		return 0, errors.New("the flags refresh and offline are mutually exclusive")
+	}
+	if noCache && (refresh || offline) {
+		return 0, errors.New("the flag no-cache can not be combined with refresh or offline")
+	}
+
+	switch {
+	case refresh:
+		return fbnd.CacheRefresh, nil
+	case offline:
+		return fbnd.CacheOffline, nil
+	default:
+		return fbnd.CacheNormal, nil
+	}
+}
+
 func version() string {
 	info, ok := debug.ReadBuildInfo()
 
@@ -64,3 +179,37 @@ func version() string {
 
 	return sb.String()
 }
+
+// printError prints err to stderr.
+// Errors caused by a changed website structure are explained in more detail,
+// so that they can be reported.
+func printError(err error) {
+	fprintError(os.Stderr, err)
+}
+
+// fprintError is like printError, but writes to w.
+func fprintError(w io.Writer, err error) {
+	fmt.Fprintln(w, err)
+
+	if errors.Is(err, fbnd.ErrNotCached) {
+		fmt.Fprintln(w)
+		fmt.Fprintln(w, "The data has not been cached yet, run the command once without the offline flag.")
+		return
+	}
+
+	if !errors.Is(err, fbnd.ErrStructureChanged) {
+		return
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "The structure of the timetable website seems to have changed, so it could not be parsed.")
+
+	var parseErr *fbnd.ParseError
+	if errors.As(err, &parseErr) {
+		fmt.Fprintf(w, "  Field:    %s\n", parseErr.Field)
+		fmt.Fprintf(w, "  Selector: %s\n", parseErr.Selector)
+		fmt.Fprintf(w, "  Snippet:  %s\n", parseErr.Snippet)
+	}
+
+	fmt.Fprintln(w, "Please report this at https://github.com/n9v9/fbnd/issues.")
+}
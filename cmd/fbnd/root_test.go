@@ -0,0 +1,31 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/fatih/color"
+)
+
+func TestDiffIgnoresClientConfig(t *testing.T) {
+	defer func(c string, noColor bool) { calendars, color.NoColor = c, noColor }(calendars, color.NoColor)
+
+	dir := t.TempDir()
+	timetable := filepath.Join(dir, "timetable.json")
+	if err := os.WriteFile(timetable, []byte(`{"days":[]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	broken := filepath.Join(dir, "calendars.json")
+	if err := os.WriteFile(broken, []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The diff command does not need a client, so an invalid calendars file must not
+	// make it exit.
+	cmd := cmdRoot()
+	cmd.SetArgs([]string{"diff", "--calendars", broken, timetable, timetable})
+	if err := cmd.Execute(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/n9v9/fbnd"
+	"github.com/spf13/cobra"
+)
+
+func cmdSearch() *cobra.Command {
+	return &cobra.Command{
+		Use:   "search <query>",
+		Short: "Search the courses of all degree programs",
+		Long: `Search the courses of all degree programs
+
+The full and short names of the courses, the full names of the professors and the
+rooms of all degree programs of both semesters are searched. Case and umlauts are
+ignored, so mueller as well as muller find Müller.
+
+Each matching course is listed together with the degree program that offers it.`,
+		Args:   cobra.MinimumNArgs(1),
+		PreRun: initClient,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runSearch(cmd.Context(), strings.Join(args, " ")); err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+		},
+	}
+}
+
+func runSearch(ctx context.Context, query string) error {
+	timetables, err := client.AllTimetables(ctx)
+	if err != nil {
+		return err
+	}
+
+	results := fbnd.Search(timetables, query)
+
+	if outputJSON {
+		if results == nil {
+			results = []fbnd.SearchResult{}
+		}
+		return json.NewEncoder(os.Stdout).Encode(results)
+	}
+
+	if len(results) == 0 {
+		fmt.Printf("No courses found for %q\n", query)
+		return nil
+	}
+
+	formatSemester := func(s fbnd.Semester) string { return fmt.Sprintf("%s %d, Semester %d", s.Cycle, s.Year, s.Term) }
+	formatHeader := func(cell string) string { return color.New(color.FgWhite, color.Bold).Sprint(cell) }
+
+	// The columns are at least as wide as their header.
+	width := func(header string, n int) int {
+		if n < len(header) {
+			return len(header)
+		}
+		return n
+	}
+	maxID := width("ID", Max(results, func(v *fbnd.SearchResult) int { return len(v.DegreeProgram.ID) }))
+	maxSemester := width("Semester", Max(results, func(v *fbnd.SearchResult) int { return len(formatSemester(v.DegreeProgram.Semester)) }))
+	maxTime := width("Time", Max(results, func(v *fbnd.SearchResult) int { return len(v.Course.Time.String()) }))
+	maxNameShort := width("Course", Max(results, func(v *fbnd.SearchResult) int { return len(v.Course.NameShort) }))
+	maxLesson := width("Lesson", Max(results, func(v *fbnd.SearchResult) int { return len(v.Course.Lesson.String()) }))
+
+	// Specifying a width for ANSI colored strings has no effect, see printTable.
+	fmt.Fprintf(color.Output, "%s%s | %s%s | %s%s | %s%s | %s%s | %s\n",
+		formatHeader("ID"), strings.Repeat(" ", maxID-len("ID")),
+		formatHeader("Semester"), strings.Repeat(" ", maxSemester-len("Semester")),
+		formatHeader("Time"), strings.Repeat(" ", maxTime-len("Time")),
+		formatHeader("Course"), strings.Repeat(" ", maxNameShort-len("Course")),
+		formatHeader("Lesson"), strings.Repeat(" ", maxLesson-len("Lesson")),
+		formatHeader("Name, Professor and Room"),
+	)
+
+	for _, v := range results {
+		fmt.Printf("%-*s | %-*s | %-*s | %-*s | %-*s | %s, %s, %s\n",
+			maxID, v.DegreeProgram.ID,
+			maxSemester, formatSemester(v.DegreeProgram.Semester),
+			maxTime, v.Course.Time,
+			maxNameShort, v.Course.NameShort,
+			maxLesson, v.Course.Lesson,
+			v.Course.NameLong, v.Course.ProfessorLong, v.Course.Room,
+		)
+	}
+
+	return nil
+}
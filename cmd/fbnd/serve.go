@@ -0,0 +1,348 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/n9v9/fbnd"
+	"github.com/spf13/cobra"
+)
+
+func cmdServe() *cobra.Command {
+	var (
+		addr string
+		ttl  time.Duration
+	)
+
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Serve degree programs and timetables as a JSON API",
+		Long: `Serve degree programs and timetables as a JSON API
+
+The following endpoints are served, using the same JSON representation as the --json flag:
+
+  GET /programs?cycle=<summer|winter>  All degree programs, of both cycles if cycle is omitted
+  GET /programs/<ID>/timetable         The timetable of a degree program
+  GET /rooms                           All rooms in which courses take place
+  GET /professors                      All professors that hold courses
+
+The API can be used by this program itself with the --api flag.
+Responses are kept in memory for the duration given by --ttl.`,
+		Args:   cobra.NoArgs,
+		PreRun: initClient,
+		Run: func(cmd *cobra.Command, _ []string) {
+			if err := runServe(cmd.Context(), addr, ttl); err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().StringVar(&addr, "addr", "localhost:8080", "Address to listen on")
+	cmd.Flags().DurationVar(&ttl, "ttl", 10*time.Minute, "Duration for which responses are kept in memory")
+
+	return cmd
+}
+
+func runServe(ctx context.Context, addr string, ttl time.Duration) error {
+	logger := log.New(os.Stderr, "", log.LstdFlags)
+	return listenAndServe(ctx, addr, newServer(client, ttl, logger).handler(), logger)
+}
+
+// listenAndServe serves handler on addr until ctx is done and then shuts the server
+// down gracefully. Every request is logged to logger.
+func listenAndServe(ctx context.Context, addr string, handler http.Handler, logger *log.Logger) error {
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           logRequests(handler, logger),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		logger.Printf("listening on %s", addr)
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	// Give running requests some time to finish.
+	logger.Println("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	return srv.Shutdown(shutdownCtx)
+}
+
+// server answers the requests of the JSON API with data fetched by client.
+type server struct {
+	client *fbnd.Client
+	ttl    time.Duration
+	logger *log.Logger
+
+	mu    sync.Mutex
+	cache map[string]cachedValue
+	// calls are the fetches that are in progress, so that concurrent requests for
+	// the same expired value wait for a single fetch.
+	calls map[string]*fetchCall
+}
+
+type cachedValue struct {
+	value   any
+	expires time.Time
+}
+
+// fetchCall is a fetch of a value that is in progress.
+// Its result is set before done is closed.
+type fetchCall struct {
+	done  chan struct{}
+	value any
+	err   error
+}
+
+func newServer(client *fbnd.Client, ttl time.Duration, logger *log.Logger) *server {
+	return &server{
+		client: client,
+		ttl:    ttl,
+		logger: logger,
+		cache:  make(map[string]cachedValue),
+		calls:  make(map[string]*fetchCall),
+	}
+}
+
+func (s *server) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/programs", s.onlyGet(s.handlePrograms))
+	mux.HandleFunc("/programs/", s.onlyGet(s.handleTimetable))
+	mux.HandleFunc("/rooms", s.onlyGet(s.handleRooms))
+	mux.HandleFunc("/professors", s.onlyGet(s.handleProfessors))
+
+	return mux
+}
+
+// onlyGet rejects all requests to next whose method is neither GET nor HEAD.
+func (s *server) onlyGet(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			s.writeError(w, &httpError{status: http.StatusMethodNotAllowed, err: errors.New("method not allowed")})
+			return
+		}
+		next(w, r)
+	}
+}
+
+// httpError is an error with the status code that is sent to the client.
+type httpError struct {
+	status int
+	err    error
+}
+
+func (e *httpError) Error() string {
+	return e.err.Error()
+}
+
+func (s *server) handlePrograms(w http.ResponseWriter, r *http.Request) {
+	var cycles []fbnd.SemesterCycle
+	switch cycle := strings.ToLower(r.URL.Query().Get("cycle")); cycle {
+	case "":
+		cycles = []fbnd.SemesterCycle{fbnd.Winter, fbnd.Summer}
+	case "summer":
+		cycles = []fbnd.SemesterCycle{fbnd.Summer}
+	case "winter":
+		cycles = []fbnd.SemesterCycle{fbnd.Winter}
+	default:
+		s.writeError(w, &httpError{status: http.StatusBadRequest, err: fmt.Errorf("invalid cycle %q, expected summer or winter", cycle)})
+		return
+	}
+
+	// Always return an array, even if there are no programs.
+	programs := []fbnd.DegreeProgram{}
+	for _, cycle := range cycles {
+		cycle := cycle
+		v, err := s.cached(r.Context(), "programs/"+string(cycle), func(ctx context.Context) (any, error) {
+			return s.client.DegreePrograms(ctx, cycle)
+		})
+		if err != nil {
+			s.writeError(w, err)
+			return
+		}
+		programs = append(programs, v.([]fbnd.DegreeProgram)...)
+	}
+
+	s.writeJSON(w, programs)
+}
+
+func (s *server) handleTimetable(w http.ResponseWriter, r *http.Request) {
+	id := strings.TrimPrefix(r.URL.Path, "/programs/")
+	id = strings.TrimSuffix(id, "/timetable")
+	if id == "" || strings.Contains(id, "/") || !strings.HasSuffix(r.URL.Path, "/timetable") {
+		http.NotFound(w, r)
+		return
+	}
+	id = strings.ToUpper(id)
+
+	v, err := s.cached(r.Context(), "timetable/"+id, func(ctx context.Context) (any, error) {
+		timetable, err := s.client.TimetableForDegreeProgram(ctx, fbnd.ID(id))
+		if err != nil {
+			return nil, err
+		}
+		if len(timetable.Days) == 0 {
+			return nil, &httpError{status: http.StatusNotFound, err: fmt.Errorf("could find no courses for degree program with id %s", id)}
+		}
+		if err := timetable.FillDegreeProgramContext(ctx); err != nil {
+			return nil, err
+		}
+		return timetable, nil
+	})
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	s.writeJSON(w, v)
+}
+
+func (s *server) handleRooms(w http.ResponseWriter, r *http.Request) {
+	timetables, err := s.allTimetables(r.Context())
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	rooms := fbnd.Rooms(timetables)
+	if rooms == nil {
+		rooms = []string{}
+	}
+	s.writeJSON(w, rooms)
+}
+
+func (s *server) handleProfessors(w http.ResponseWriter, r *http.Request) {
+	timetables, err := s.allTimetables(r.Context())
+	if err != nil {
+		s.writeError(w, err)
+		return
+	}
+
+	professors := fbnd.Professors(timetables)
+	if professors == nil {
+		professors = []fbnd.Professor{}
+	}
+	s.writeJSON(w, professors)
+}
+
+func (s *server) allTimetables(ctx context.Context) ([]*fbnd.Timetable, error) {
+	v, err := s.cached(ctx, "timetables", func(ctx context.Context) (any, error) {
+		return s.client.AllTimetables(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]*fbnd.Timetable), nil
+}
+
+// cached returns the value stored under key if it has not expired yet.
+// Otherwise fetch is called and its result is stored, unless it fails.
+// Only one fetch per key runs at a time, concurrent calls wait for its result.
+// The values must not be modified, because they are shared between requests.
+func (s *server) cached(ctx context.Context, key string, fetch func(ctx context.Context) (any, error)) (any, error) {
+	for {
+		s.mu.Lock()
+		if v, ok := s.cache[key]; ok && time.Now().Before(v.expires) {
+			s.mu.Unlock()
+			return v.value, nil
+		}
+		if c, ok := s.calls[key]; ok {
+			s.mu.Unlock()
+
+			select {
+			case <-c.done:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			// The request that started the fetch may have been canceled,
+			// in which case it is tried again for this one.
+			if c.err != nil && ctx.Err() == nil && (errors.Is(c.err, context.Canceled) || errors.Is(c.err, context.DeadlineExceeded)) {
+				continue
+			}
+			return c.value, c.err
+		}
+
+		c := &fetchCall{done: make(chan struct{})}
+		s.calls[key] = c
+		s.mu.Unlock()
+
+		c.value, c.err = fetch(ctx)
+
+		s.mu.Lock()
+		if c.err == nil {
+			s.cache[key] = cachedValue{value: c.value, expires: time.Now().Add(s.ttl)}
+		}
+		delete(s.calls, key)
+		s.mu.Unlock()
+		close(c.done)
+
+		return c.value, c.err
+	}
+}
+
+func (s *server) writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		s.logger.Printf("could not write response: %v", err)
+	}
+}
+
+// writeError writes err as a JSON object with an error field.
+// Errors that are not an httpError are caused by fetching the data.
+func (s *server) writeError(w http.ResponseWriter, err error) {
+	status := http.StatusBadGateway
+	var httpErr *httpError
+	if errors.As(err, &httpErr) {
+		status = httpErr.status
+	} else if errors.Is(err, context.Canceled) {
+		// The client is gone, so nobody reads the response anyway.
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(struct {
+		Error string `json:"error"`
+	}{err.Error()})
+}
+
+// statusRecorder remembers the status code written to a http.ResponseWriter.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// logRequests logs the method, path, status code and duration of every request.
+func logRequests(next http.Handler, logger *log.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rec, r)
+
+		logger.Printf("%s %s %d %s", r.Method, r.URL.RequestURI(), rec.status, time.Since(start).Round(time.Millisecond))
+	})
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/n9v9/fbnd"
+	"github.com/n9v9/fbnd/fbndtest"
+)
+
+func TestServe(t *testing.T) {
+	upstream := fbndtest.NewSampleServer()
+	defer upstream.Close()
+
+	srv := httptest.NewServer(newServer(upstream.Client(), time.Minute, log.New(io.Discard, "", 0)).handler())
+	defer srv.Close()
+
+	// The API is compatible with the JSON source of the library.
+	api := fbnd.NewClient(fbnd.WithBaseURL(srv.URL), fbnd.WithJSONAPI())
+	ctx := context.Background()
+
+	gotPrograms, err := api.DegreePrograms(ctx, fbnd.Summer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := fbndtest.SamplePrograms()[2:]; !reflect.DeepEqual(gotPrograms, want) {
+		t.Fatalf("want programs %v, got %v", want, gotPrograms)
+	}
+
+	timetable, err := api.TimetableForDegreeProgram(ctx, "bi2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if timetable.DegreeProgram == nil || *timetable.DegreeProgram != fbndtest.SamplePrograms()[2] {
+		t.Fatalf("want degree program %v, got %v", fbndtest.SamplePrograms()[2], timetable.DegreeProgram)
+	}
+	if len(timetable.Days) != 1 || !reflect.DeepEqual(timetable.Days[0].Courses, fbndtest.SampleCourses()[:1]) {
+		t.Fatalf("want courses %v, got %v", fbndtest.SampleCourses()[:1], timetable.Days)
+	}
+
+	// Cached responses do not cause further upstream requests.
+	requests := upstream.Requests()
+	if _, err := api.TimetableForDegreeProgram(ctx, "BI2"); err != nil {
+		t.Fatal(err)
+	}
+	if upstream.Requests() != requests {
+		t.Fatalf("want %d upstream requests, got %d", requests, upstream.Requests())
+	}
+
+	type testCase struct {
+		name       string
+		path       string
+		wantStatus int
+		wantBody   any
+	}
+
+	testCases := []testCase{
+		{name: "Rooms", path: "/rooms", wantStatus: http.StatusOK, wantBody: []any{"B 1.10", "Z 2.10"}},
+		{
+			name:       "Professors",
+			path:       "/professors",
+			wantStatus: http.StatusOK,
+			wantBody: []any{
+				map[string]any{"short": "Mül", "long": "Prof. Dr. Müller"},
+				map[string]any{"short": "Sch", "long": "Prof. Dr. Schmidt"},
+			},
+		},
+		{name: "UnknownProgram", path: "/programs/XX/timetable", wantStatus: http.StatusNotFound},
+		{name: "InvalidCycle", path: "/programs?cycle=spring", wantStatus: http.StatusBadRequest},
+		{name: "UnknownPath", path: "/programs/BI1", wantStatus: http.StatusNotFound},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			resp, err := http.Get(srv.URL + test.path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+
+			if resp.StatusCode != test.wantStatus {
+				t.Fatalf("want status %d, got %d", test.wantStatus, resp.StatusCode)
+			}
+			if test.wantBody == nil {
+				return
+			}
+
+			var got any
+			if err := json.NewDecoder(resp.Body).Decode(&got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, test.wantBody) {
+				t.Fatalf("want body %v, got %v", test.wantBody, got)
+			}
+		})
+	}
+}
+
+func TestServeCachedFetchesOnce(t *testing.T) {
+	s := newServer(nil, time.Minute, log.New(io.Discard, "", 0))
+
+	var calls int32
+	started, release := make(chan struct{}), make(chan struct{})
+	fetch := func(context.Context) (any, error) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			close(started)
+		}
+		<-release
+		return "value", nil
+	}
+
+	var wg sync.WaitGroup
+	get := func() {
+		defer wg.Done()
+		if v, err := s.cached(context.Background(), "key", fetch); err != nil || v != "value" {
+			t.Errorf("want value, got %v and error %v", v, err)
+		}
+	}
+
+	// The other requests are started while the first one is still fetching.
+	wg.Add(1)
+	go get()
+	<-started
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go get()
+	}
+	// Without waiting, the other requests might only look up the value after it has
+	// been stored. Waiting can not make the test fail if only one fetch is made.
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if calls != 1 {
+		t.Fatalf("want 1 fetch, got %d", calls)
+	}
+}
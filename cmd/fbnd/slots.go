@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/n9v9/fbnd"
+)
+
+// defaultSlotsFile returns the path of the slots file in the user's config directory.
+func defaultSlotsFile() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "fbnd", "slots.json"), nil
+}
+
+// slotOptions reads the slots file at path and returns an option that replaces
+// fbnd.FB03Slots with its table, for example:
+//
+//	{
+//	  "8": {"start": "08:15", "end": "09:00"},
+//	  "9": {"start": "09:00", "end": "09:45"}
+//	}
+//
+// If path is empty, the default slots file is read if it exists.
+func slotOptions(path string) ([]fbnd.Option, error) {
+	optional := path == ""
+	if optional {
+		var err error
+		if path, err = defaultSlotsFile(); err != nil {
+			return nil, nil
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if optional && errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("could not read slots: %w", err)
+	}
+
+	var slots fbnd.SlotTable
+	if err := json.Unmarshal(data, &slots); err != nil {
+		return nil, fmt.Errorf("could not parse slots in %s: %w", path, err)
+	}
+	for hour, v := range slots {
+		if !v.Start.Before(v.End) {
+			return nil, fmt.Errorf("invalid slot of hour %d in %s, it has to start before %s", hour, path, v.End)
+		}
+	}
+
+	return []fbnd.Option{fbnd.WithSlotTable(slots)}, nil
+}
@@ -0,0 +1,29 @@
+//go:build !linux && !darwin && !dragonfly && !freebsd && !netbsd && !openbsd
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+)
+
+// terminal is not supported on this platform, so openTerminal always fails.
+type terminal struct {
+	in  *os.File
+	out *os.File
+}
+
+func openTerminal(_, _ *os.File) (*terminal, error) {
+	return nil, fmt.Errorf("the terminal user interface is not supported on %s", runtime.GOOS)
+}
+
+func (t *terminal) restore() error { return nil }
+
+func (t *terminal) size() (width, height int, err error) { return terminalSize(t.out) }
+
+func terminalSize(*os.File) (width, height int, err error) {
+	return 0, 0, fmt.Errorf("the size of the terminal is not supported on %s", runtime.GOOS)
+}
+
+func notifyResize(chan<- os.Signal) {}
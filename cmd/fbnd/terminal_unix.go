@@ -0,0 +1,67 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// terminal is a terminal in raw mode, in which input is neither echoed nor buffered
+// by lines and output is not processed, so newlines need a carriage return.
+type terminal struct {
+	in    *os.File
+	out   *os.File
+	state unix.Termios
+}
+
+// openTerminal puts the terminal of in into raw mode. The previous mode is restored
+// by restore.
+func openTerminal(in, out *os.File) (*terminal, error) {
+	fd := int(in.Fd())
+	state, err := unix.IoctlGetTermios(fd, ioctlGetTermios)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := *state
+	raw.Iflag &^= unix.IGNBRK | unix.BRKINT | unix.PARMRK | unix.ISTRIP | unix.INLCR | unix.IGNCR | unix.ICRNL | unix.IXON
+	raw.Oflag &^= unix.OPOST
+	raw.Lflag &^= unix.ECHO | unix.ECHONL | unix.ICANON | unix.ISIG | unix.IEXTEN
+	raw.Cflag &^= unix.CSIZE | unix.PARENB
+	raw.Cflag |= unix.CS8
+	raw.Cc[unix.VMIN] = 1
+	raw.Cc[unix.VTIME] = 0
+	if err := unix.IoctlSetTermios(fd, ioctlSetTermios, &raw); err != nil {
+		return nil, err
+	}
+
+	return &terminal{in: in, out: out, state: *state}, nil
+}
+
+// restore restores the mode of the terminal from before openTerminal.
+func (t *terminal) restore() error {
+	return unix.IoctlSetTermios(int(t.in.Fd()), ioctlSetTermios, &t.state)
+}
+
+// size returns the number of columns and rows of the terminal.
+func (t *terminal) size() (width, height int, err error) {
+	return terminalSize(t.out)
+}
+
+// terminalSize returns the number of columns and rows of the terminal of f.
+func terminalSize(f *os.File) (width, height int, err error) {
+	ws, err := unix.IoctlGetWinsize(int(f.Fd()), unix.TIOCGWINSZ)
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(ws.Col), int(ws.Row), nil
+}
+
+// notifyResize relays a signal to c whenever the terminal is resized.
+func notifyResize(c chan<- os.Signal) {
+	signal.Notify(c, syscall.SIGWINCH)
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
@@ -8,29 +9,44 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/n9v9/fbnd"
+	"github.com/n9v9/fbnd/holiday"
 	"github.com/spf13/cobra"
 )
 
 func cmdTime() *cobra.Command {
-	return &cobra.Command{
+	var (
+		grid  bool
+		width int
+	)
+
+	cmd := &cobra.Command{
 		Use:   "time",
 		Short: "Display the timetable for a specific degree program",
 		Long: `Display the timetable for a specific degree program
 
 This command expects the ID of the degree program for which to display the timetable.
-If you do not know the ID, you can see all available ones by calling the list command.`,
-		Args: cobra.ExactArgs(1),
-		Run: func(_ *cobra.Command, args []string) {
-			if err := runTime(args[0]); err != nil {
-				fmt.Fprintln(os.Stderr, err)
+If you do not know the ID, you can see all available ones by calling the list command.
+
+With --grid, the timetable is drawn as a grid of the weekdays and hours that fits
+into the width of the terminal, in which each course spans the rows of its hours.`,
+		Args:   cobra.ExactArgs(1),
+		PreRun: initClient,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runTime(cmd.Context(), args[0], grid, width); err != nil {
+				printError(err)
 				os.Exit(1)
 			}
 		},
 	}
+
+	cmd.Flags().BoolVar(&grid, "grid", false, "Display the timetable as a weekly grid")
+	cmd.Flags().IntVar(&width, "width", 0, "Maximum width of the grid (default width of the terminal)")
+
+	return cmd
 }
 
-func runTime(id string) error {
-	timetable, err := fbnd.TimetableForDegreeProgram(fbnd.ID(id))
+func runTime(ctx context.Context, id string, grid bool, width int) error {
+	timetable, err := client.TimetableForDegreeProgram(ctx, fbnd.ID(id))
 	if err != nil {
 		return err
 	}
@@ -41,58 +57,62 @@ func runTime(id string) error {
 	if outputJSON {
 		// When we output JSON we want to get the accompanying DegreeProgram.
 		if timetable.DegreeProgram == nil {
-			if err := timetable.FillDegreeProgram(); err != nil {
+			if err := timetable.FillDegreeProgramContext(ctx); err != nil {
 				return err
 			}
 		}
 		return json.NewEncoder(os.Stdout).Encode(timetable)
 	}
 
+	if grid {
+		if width <= 0 {
+			width = defaultGridWidth
+			if w, _, err := terminalSize(os.Stdout); err == nil && w > 0 {
+				width = w
+			}
+		}
+		printGrid(timetable, width)
+		return nil
+	}
+
+	printTimetable(timetable)
+	return nil
+}
+
+// defaultGridWidth is the width of the grid if the width of the terminal is unknown,
+// for example because the output is redirected.
+const defaultGridWidth = 100
+
+// printTimetable prints the courses of timetable grouped by their weekday.
+// The current and next courses of today are highlighted, unless there are no lectures today.
+func printTimetable(timetable *fbnd.Timetable) {
 	printlnWeekday := color.New(color.FgWhite, color.Underline, color.Bold).PrintlnFunc()
 	printlnWeekdayToday := color.New(color.FgYellow, color.Underline, color.Bold).PrintlnFunc()
 	printlnCourse := color.New(color.FgBlue, color.Bold).PrintlnFunc()
 	printlnNextCourse := color.New(color.FgBlue).PrintlnFunc()
 
-	currentHour := time.Now().Hour()
-
-	nextCourseIndexes := func(courses []fbnd.Course) map[int]struct{} {
-		// Map all start hours to the corresponding index into courses.
-		startHours := make(map[int][]int)
-		for i, v := range courses {
-			startHours[v.Time.HourStart] = append(startHours[v.Time.HourStart], i)
-		}
-
-		// Now we save the nearest next courses.
-		var (
-			currentMin  []int
-			nextMinHour *int
-		)
-		for k, v := range startHours {
-			// This is important because the outer k does not change, only the outer k's value.
-			k := k
-			if k > currentHour && (nextMinHour == nil || k < *nextMinHour) {
-				nextMinHour = &k
-				currentMin = v
-			}
-		}
-
-		// Build the result of next courses where the key is the index into courses.
-		res := make(map[int]struct{})
-		for _, courseIndex := range currentMin {
-			res[courseIndex] = struct{}{}
-		}
-		return res
-	}
+	now := time.Now().In(fbnd.Location)
+	currentClock := fbnd.ClockOf(now)
+	// Courses are only highlighted if they take place today. The breaks of the semester
+	// are only known if the degree program is, holidays are always known.
+	lectureDay := timetable.IsLectureDay(now)
 
 	for _, day := range timetable.Days {
 		var (
-			isToday = day.Weekday == time.Now().Weekday()
+			isToday = day.Weekday == now.Weekday()
 			next    map[int]struct{}
 		)
 
-		if isToday {
+		if isToday && !lectureDay {
+			if h, ok := holiday.Lookup(now.In(fbnd.Location)); ok {
+				printlnWeekdayToday(fmt.Sprintf("%s (%s, no lectures)", day.Weekday, h.Name))
+			} else {
+				printlnWeekdayToday(fmt.Sprintf("%s (no lectures)", day.Weekday))
+			}
+			isToday = false
+		} else if isToday {
 			printlnWeekdayToday(day.Weekday)
-			next = nextCourseIndexes(day.Courses)
+			next = nextCourseIndexes(day.Courses, currentClock)
 		} else {
 			printlnWeekday(day.Weekday)
 		}
@@ -101,15 +121,18 @@ func runTime(id string) error {
 		maxLesson := Max(day.Courses, func(v *fbnd.Course) int { return len(v.Lesson.String()) })
 		maxProfessorShort := Max(day.Courses, func(v *fbnd.Course) int { return len(v.ProfessorShort) })
 
+		markers := parallelMarkers(day.Courses)
+
 		for i, v := range day.Courses {
-			line := fmt.Sprintf("%02d - %02d | %-*s | %-*s | %0-*s | %s",
-				v.Time.HourStart, v.Time.HourEnd,
+			line := fmt.Sprintf("%s%s - %s | %-*s | %-*s | %0-*s | %s",
+				markers[i],
+				v.Time.Start, v.Time.End,
 				maxNameShort, v.NameShort,
 				maxLesson, v.Lesson,
 				maxProfessorShort, v.ProfessorShort,
 				v.Room)
 
-			if isToday && currentHour >= v.Time.HourStart && currentHour < v.Time.HourEnd {
+			if lectureDay && v.Time.Contains(now) {
 				// Highlight the current course.
 				printlnCourse(line)
 				continue
@@ -126,6 +149,86 @@ func runTime(id string) error {
 			fmt.Println(line)
 		}
 	}
+}
 
-	return nil
+// nextCourseIndexes returns the indexes of the courses that start next after clock.
+// These are multiple ones if several courses start at the same time.
+func nextCourseIndexes(courses []fbnd.Course, clock fbnd.Clock) map[int]struct{} {
+	// Map all start times to the corresponding index into courses.
+	startClocks := make(map[fbnd.Clock][]int)
+	for i, v := range courses {
+		startClocks[v.Time.Start] = append(startClocks[v.Time.Start], i)
+	}
+
+	// Now we save the nearest next courses.
+	var (
+		currentMin   []int
+		nextMinClock *fbnd.Clock
+	)
+	for k, v := range startClocks {
+		// This is important because the outer k does not change, only the outer k's value.
+		k := k
+		if clock.Before(k) && (nextMinClock == nil || k.Before(*nextMinClock)) {
+			nextMinClock = &k
+			currentMin = v
+		}
+	}
+
+	// Build the result of next courses where the key is the index into courses.
+	res := make(map[int]struct{})
+	for _, courseIndex := range currentMin {
+		res[courseIndex] = struct{}{}
+	}
+	return res
+}
+
+// parallelMarkers returns a prefix for each course that visually groups courses
+// which take place at the same time, for example exercises of different groups:
+//
+//	┌ 10:00 - 11:30 | MA1-A | Exercise | ...
+//	└ 10:00 - 11:30 | MA1-B | Exercise | ...
+//
+// The courses must be sorted by their start hour.
+// If no courses overlap, all prefixes are empty.
+func parallelMarkers(courses []fbnd.Course) []string {
+	markers := make([]string, len(courses))
+
+	// Find groups of courses where each course overlaps with at least one previous course of the group.
+	var (
+		groups   [][]int
+		groupEnd int
+		parallel bool
+	)
+	for i, v := range courses {
+		if len(groups) > 0 && v.Time.HourStart < groupEnd {
+			groups[len(groups)-1] = append(groups[len(groups)-1], i)
+			parallel = true
+		} else {
+			groups = append(groups, []int{i})
+		}
+		if len(groups[len(groups)-1]) == 1 || v.Time.HourEnd > groupEnd {
+			groupEnd = v.Time.HourEnd
+		}
+	}
+
+	if !parallel {
+		return markers
+	}
+
+	for _, group := range groups {
+		for j, courseIndex := range group {
+			switch {
+			case len(group) == 1:
+				markers[courseIndex] = "  "
+			case j == 0:
+				markers[courseIndex] = "┌ "
+			case j == len(group)-1:
+				markers[courseIndex] = "└ "
+			default:
+				markers[courseIndex] = "│ "
+			}
+		}
+	}
+
+	return markers
 }
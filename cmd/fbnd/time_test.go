@@ -0,0 +1,52 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/n9v9/fbnd"
+)
+
+func TestParallelMarkers(t *testing.T) {
+	type testCase struct {
+		name  string
+		hours [][2]int
+		want  []string
+	}
+
+	testCases := []testCase{
+		{
+			name:  "NoCourses",
+			hours: nil,
+			want:  []string{},
+		},
+		{
+			name:  "NoParallelCourses",
+			hours: [][2]int{{8, 10}, {10, 12}},
+			want:  []string{"", ""},
+		},
+		{
+			name:  "TwoParallelCourses",
+			hours: [][2]int{{8, 10}, {10, 12}, {10, 12}},
+			want:  []string{"  ", "┌ ", "└ "},
+		},
+		{
+			name:  "ChainOfOverlappingCourses",
+			hours: [][2]int{{8, 12}, {9, 10}, {11, 13}, {13, 14}},
+			want:  []string{"┌ ", "│ ", "└ ", "  "},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			courses := make([]fbnd.Course, len(test.hours))
+			for i, v := range test.hours {
+				courses[i].Time = fbnd.Time{HourStart: v[0], HourEnd: v[1]}
+			}
+
+			if got := parallelMarkers(courses); !reflect.DeepEqual(test.want, got) {
+				t.Fatalf("want %q, got %q", test.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,625 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/fatih/color"
+	"github.com/n9v9/fbnd"
+	"github.com/spf13/cobra"
+)
+
+func cmdTUI() *cobra.Command {
+	return &cobra.Command{
+		Use:   "tui [ID]",
+		Short: "Browse the timetables of all degree programs in an interactive user interface",
+		Long: `Browse the timetables of all degree programs in an interactive user interface
+
+The degree programs of both semesters are listed first, type to search them and press
+enter to open the timetable of the selected one. If an ID is given, its timetable is
+opened directly.
+
+The timetable is shown as a weekly grid, the current and next courses of today are
+highlighted like by the time command. The details of the selected course are shown
+below the grid.
+
+  ←/→ or h/l   Select the previous or next day.
+  ↑/↓ or k/j   Select the previous or next course of the day.
+  /            Search the courses, only matching ones can be selected.
+  esc          Clear the search or go back to the degree programs.
+  q or ctrl+c  Quit.`,
+		Args:   cobra.MaximumNArgs(1),
+		PreRun: initClient,
+		Run: func(cmd *cobra.Command, args []string) {
+			var id string
+			if len(args) == 1 {
+				id = args[0]
+			}
+			if err := runTUI(cmd.Context(), id); err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+		},
+	}
+}
+
+func runTUI(ctx context.Context, id string) error {
+	var programs []fbnd.DegreeProgram
+	for _, cycle := range []fbnd.SemesterCycle{fbnd.Winter, fbnd.Summer} {
+		p, err := client.DegreePrograms(ctx, cycle)
+		if err != nil {
+			return err
+		}
+		programs = append(programs, p...)
+	}
+	sort.SliceStable(programs, func(i, j int) bool {
+		return programs[i].ID < programs[j].ID
+	})
+
+	m := newTUIModel(programs)
+	load := func() {
+		timetable, err := client.TimetableForDegreeProgram(ctx, m.program.ID)
+		m.setTimetable(timetable, err)
+	}
+	if id != "" {
+		if !m.open(fbnd.ID(strings.ToUpper(id))) {
+			return fmt.Errorf("could find no degree program with id %s", id)
+		}
+		load()
+		if m.err != nil {
+			return m.err
+		}
+	}
+
+	term, err := openTerminal(os.Stdin, os.Stdout)
+	if err != nil {
+		return fmt.Errorf("could not open terminal: %w", err)
+	}
+	defer term.restore()
+
+	// Use the alternate screen, so that the previous content of the terminal is
+	// shown again after quitting, and hide the cursor.
+	fmt.Fprint(term.out, "\x1b[?1049h\x1b[?25l")
+	defer fmt.Fprint(term.out, "\x1b[?25h\x1b[?1049l")
+
+	keys := make(chan []string)
+	go func() {
+		defer close(keys)
+		buf := make([]byte, 256)
+		for {
+			n, err := term.in.Read(buf)
+			if err != nil {
+				return
+			}
+			keys <- parseKeys(buf[:n])
+		}
+	}()
+
+	resize := make(chan os.Signal, 1)
+	notifyResize(resize)
+
+	// Redraw regularly, so that the highlighted courses stay up to date.
+	ticker := time.NewTicker(30 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		draw(term, m)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		case <-resize:
+		case ks, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			for _, k := range ks {
+				switch m.update(k) {
+				case tuiQuit:
+					return nil
+				case tuiLoad:
+					draw(term, m)
+					load()
+				}
+			}
+		}
+	}
+}
+
+// draw renders m to the whole screen of term.
+func draw(term *terminal, m *tuiModel) {
+	width, height, err := term.size()
+	if err != nil || width <= 0 || height <= 0 {
+		width, height = 80, 24
+	}
+	m.now = time.Now().In(fbnd.Location)
+
+	var sb strings.Builder
+	sb.WriteString("\x1b[H")
+	for i, line := range m.view(width, height) {
+		if i > 0 {
+			sb.WriteString("\r\n")
+		}
+		sb.WriteString(line)
+		sb.WriteString("\x1b[K")
+	}
+	sb.WriteString("\x1b[J")
+	fmt.Fprint(term.out, sb.String())
+}
+
+// Keys that are not a single printable character.
+const (
+	keyUp        = "up"
+	keyDown      = "down"
+	keyLeft      = "left"
+	keyRight     = "right"
+	keyEnter     = "enter"
+	keyEscape    = "esc"
+	keyBackspace = "backspace"
+	keyCtrlC     = "ctrl+c"
+)
+
+// parseKeys parses the input of a terminal in raw mode into keys, which are either
+// one of the key constants or a single printable character.
+func parseKeys(b []byte) []string {
+	var keys []string
+	for len(b) > 0 {
+		switch {
+		case b[0] == 0x1b && len(b) >= 3 && (b[1] == '[' || b[1] == 'O'):
+			arrows := map[byte]string{'A': keyUp, 'B': keyDown, 'C': keyRight, 'D': keyLeft}
+			if k, ok := arrows[b[2]]; ok {
+				keys = append(keys, k)
+				b = b[3:]
+				continue
+			}
+			// Skip other escape sequences up to their final byte.
+			i := 2
+			for i < len(b) && (b[i] < 0x40 || b[i] > 0x7e) {
+				i++
+			}
+			if i < len(b) {
+				i++
+			}
+			b = b[i:]
+		case b[0] == 0x1b:
+			keys = append(keys, keyEscape)
+			b = b[1:]
+		case b[0] == '\r' || b[0] == '\n':
+			keys = append(keys, keyEnter)
+			b = b[1:]
+		case b[0] == 0x7f || b[0] == 0x08:
+			keys = append(keys, keyBackspace)
+			b = b[1:]
+		case b[0] == 0x03:
+			keys = append(keys, keyCtrlC)
+			b = b[1:]
+		case b[0] < 0x20:
+			b = b[1:]
+		default:
+			r, size := utf8.DecodeRune(b)
+			if r != utf8.RuneError {
+				keys = append(keys, string(r))
+			}
+			b = b[size:]
+		}
+	}
+	return keys
+}
+
+// tuiAction is returned by tuiModel.update to tell runTUI what to do.
+type tuiAction int
+
+const (
+	tuiNone tuiAction = iota
+	tuiQuit
+	// tuiLoad requests the timetable of the selected program to be loaded.
+	tuiLoad
+)
+
+// tuiModel is the state of the terminal user interface, which is either the list of
+// degree programs or the timetable of the selected one.
+type tuiModel struct {
+	programs []fbnd.DegreeProgram
+	filtered []fbnd.DegreeProgram
+	cursor   int
+	filter   string
+
+	program   fbnd.DegreeProgram
+	timetable *fbnd.Timetable
+	loading   bool
+	err       error
+
+	weekday   time.Weekday
+	course    int
+	query     string
+	searching bool
+	matches   map[fbnd.Course]bool
+
+	now time.Time
+}
+
+func newTUIModel(programs []fbnd.DegreeProgram) *tuiModel {
+	m := &tuiModel{programs: programs, now: time.Now().In(fbnd.Location)}
+	m.filterPrograms()
+	return m
+}
+
+// open selects the degree program with the given ID for loading.
+func (m *tuiModel) open(id fbnd.ID) bool {
+	for _, v := range m.programs {
+		if v.ID == id {
+			m.program = v
+			m.loading = true
+			return true
+		}
+	}
+	return false
+}
+
+// setTimetable shows the loaded timetable of the selected degree program and selects
+// the current or next course of today, or the first course of the week.
+func (m *tuiModel) setTimetable(timetable *fbnd.Timetable, err error) {
+	m.loading = false
+	m.err = err
+	if err != nil {
+		return
+	}
+	if len(timetable.Days) == 0 {
+		m.err = fmt.Errorf("could find no courses for degree program with id %s", m.program.ID)
+		return
+	}
+
+	program := m.program
+	timetable.DegreeProgram = &program
+	m.timetable = timetable
+	m.query, m.searching, m.matches = "", false, nil
+
+	m.weekday, m.course = timetable.Days[0].Weekday, 0
+	for _, day := range timetable.Days {
+		if day.Weekday != m.now.Weekday() {
+			continue
+		}
+		m.weekday = day.Weekday
+		for i, v := range day.Courses {
+			if fbnd.ClockOf(m.now).Before(v.Time.End) {
+				m.course = i
+				break
+			}
+		}
+	}
+}
+
+func (m *tuiModel) update(key string) tuiAction {
+	if key == keyCtrlC {
+		return tuiQuit
+	}
+	if m.loading {
+		return tuiNone
+	}
+	if m.timetable == nil {
+		return m.updatePrograms(key)
+	}
+	return m.updateTimetable(key)
+}
+
+func (m *tuiModel) updatePrograms(key string) tuiAction {
+	m.err = nil
+
+	switch key {
+	case keyEscape:
+		if m.filter == "" {
+			return tuiQuit
+		}
+		m.filter = ""
+		m.filterPrograms()
+	case keyUp:
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case keyDown:
+		if m.cursor < len(m.filtered)-1 {
+			m.cursor++
+		}
+	case keyEnter:
+		if len(m.filtered) > 0 {
+			m.open(m.filtered[m.cursor].ID)
+			return tuiLoad
+		}
+	case keyBackspace:
+		m.filter = withoutLastRune(m.filter)
+		m.filterPrograms()
+	default:
+		if utf8.RuneCountInString(key) == 1 {
+			m.filter += key
+			m.filterPrograms()
+		}
+	}
+	return tuiNone
+}
+
+func (m *tuiModel) filterPrograms() {
+	m.filtered = nil
+	query := strings.ToLower(m.filter)
+	for _, v := range m.programs {
+		if strings.Contains(strings.ToLower(formatProgram(v)), query) {
+			m.filtered = append(m.filtered, v)
+		}
+	}
+	m.cursor = 0
+}
+
+func (m *tuiModel) updateTimetable(key string) tuiAction {
+	if m.searching {
+		switch key {
+		case keyEscape:
+			m.searching = false
+			m.setQuery("")
+			return tuiNone
+		case keyEnter:
+			m.searching = false
+			return tuiNone
+		case keyBackspace:
+			m.setQuery(withoutLastRune(m.query))
+			return tuiNone
+		case keyUp, keyDown, keyLeft, keyRight:
+		default:
+			if utf8.RuneCountInString(key) == 1 {
+				m.setQuery(m.query + key)
+			}
+			return tuiNone
+		}
+	}
+
+	switch key {
+	case "q":
+		return tuiQuit
+	case keyEscape, keyBackspace:
+		if m.query != "" {
+			m.setQuery("")
+			break
+		}
+		m.timetable = nil
+		m.err = nil
+	case "/":
+		m.searching = true
+	case keyLeft, "h":
+		m.moveDay(-1)
+	case keyRight, "l":
+		m.moveDay(1)
+	case keyUp, "k":
+		m.moveCourse(-1)
+	case keyDown, "j":
+		m.moveCourse(1)
+	}
+	return tuiNone
+}
+
+// setQuery searches the courses of the timetable for query and selects the first
+// matching course, if the selected one does not match.
+func (m *tuiModel) setQuery(query string) {
+	m.query = query
+	m.matches = nil
+	if strings.TrimSpace(query) != "" {
+		m.matches = make(map[fbnd.Course]bool)
+		for _, v := range fbnd.Search([]*fbnd.Timetable{m.timetable}, query) {
+			m.matches[v.Course] = true
+		}
+	}
+
+	courses := m.courses(m.weekday)
+	if m.course >= 0 && m.course < len(courses) && m.selectable(courses[m.course]) {
+		return
+	}
+	m.course = -1
+	m.moveCourse(1)
+}
+
+func (m *tuiModel) selectable(c fbnd.Course) bool {
+	return m.matches == nil || m.matches[c]
+}
+
+func (m *tuiModel) moveDay(delta int) {
+	weekdays := m.weekdays()
+	for i, v := range weekdays {
+		if v == m.weekday && i+delta >= 0 && i+delta < len(weekdays) {
+			m.weekday = weekdays[i+delta]
+			m.course = -1
+			m.moveCourse(1)
+			return
+		}
+	}
+}
+
+// moveCourse selects the next selectable course of the day in the given direction.
+// If there is none, the selection stays, unless the selected course is not selectable.
+func (m *tuiModel) moveCourse(delta int) {
+	courses := m.courses(m.weekday)
+	for i := m.course + delta; i >= 0 && i < len(courses); i += delta {
+		if m.selectable(courses[i]) {
+			m.course = i
+			return
+		}
+	}
+	if m.course < 0 || m.course >= len(courses) || !m.selectable(courses[m.course]) {
+		m.course = -1
+	}
+}
+
+// weekdays returns the weekdays that are shown in the grid, see gridWeekdays.
+func (m *tuiModel) weekdays() []time.Weekday {
+	return gridWeekdays(m.timetable)
+}
+
+func (m *tuiModel) courses(weekday time.Weekday) []fbnd.Course {
+	for _, day := range m.timetable.Days {
+		if day.Weekday == weekday {
+			return day.Courses
+		}
+	}
+	return nil
+}
+
+// selected returns the selected course, if there is one.
+func (m *tuiModel) selected() (fbnd.Course, bool) {
+	courses := m.courses(m.weekday)
+	if m.course < 0 || m.course >= len(courses) {
+		return fbnd.Course{}, false
+	}
+	return courses[m.course], true
+}
+
+// view returns the lines of the screen with the given size.
+func (m *tuiModel) view(width, height int) []string {
+	var lines []string
+	if m.timetable == nil {
+		lines = m.viewPrograms(width, height)
+	} else {
+		lines = m.viewTimetable(width, height)
+	}
+	if len(lines) > height {
+		lines = lines[:height]
+	}
+	return lines
+}
+
+func (m *tuiModel) viewPrograms(width, height int) []string {
+	bold := color.New(color.FgWhite, color.Bold)
+	selected := color.New(color.ReverseVideo)
+
+	lines := []string{
+		bold.Sprint(fit("Degree programs", width)),
+		fit("Search: "+m.filter+"_", width),
+		"",
+	}
+
+	// Scroll the list, so that the selected degree program is visible.
+	rows := height - len(lines) - 2
+	if rows < 1 {
+		rows = 1
+	}
+	offset := 0
+	if m.cursor >= rows {
+		offset = m.cursor - rows + 1
+	}
+	for i := offset; i < len(m.filtered) && i < offset+rows; i++ {
+		if i == m.cursor {
+			lines = append(lines, selected.Sprint(fit("> "+formatProgram(m.filtered[i]), width)))
+		} else {
+			lines = append(lines, fit("  "+formatProgram(m.filtered[i]), width))
+		}
+	}
+	if len(m.filtered) == 0 {
+		lines = append(lines, fit("  No degree programs found", width))
+	}
+
+	for len(lines) < height-1 {
+		lines = append(lines, "")
+	}
+	return append(lines, m.status(width, "↑/↓ select  enter open  type to search  esc quit"))
+}
+
+func (m *tuiModel) viewTimetable(width, height int) []string {
+	bold := color.New(color.FgWhite, color.Bold)
+
+	lines := []string{bold.Sprint(fit(formatProgram(m.program), width)), ""}
+
+	// The details and status take 7 lines, the rest is left for the grid.
+	grid := m.grid(width, height-len(lines)-7)
+	lines = append(lines, grid...)
+	lines = append(lines, "")
+	lines = append(lines, m.details(width)...)
+
+	for len(lines) < height-1 {
+		lines = append(lines, "")
+	}
+	if m.searching {
+		return append(lines, fit("/"+m.query+"_", width))
+	}
+	return append(lines, m.status(width, "←/→ day  ↑/↓ course  / search  esc back  q quit"))
+}
+
+// grid returns the lines of the grid of the timetable, see renderGrid, with at most the
+// given number of lines. The hours are scrolled, so that the selected course is visible.
+func (m *tuiModel) grid(width, maxLines int) []string {
+	opts := gridOptions{now: m.now, day: &m.weekday, course: m.course, matches: m.matches}
+	lines := renderGrid(m.timetable, width, opts)
+	rows := maxLines - gridHeaderLines
+	if len(lines) <= maxLines || rows < 1 {
+		return lines
+	}
+
+	// Each hour takes two lines, the selected course ends with the line below its last hour.
+	offset := 0
+	if selected, ok := m.selected(); ok {
+		firstHour, _ := gridHours(m.timetable)
+		start := 2 * (selected.Time.HourStart - firstHour)
+		end := 2 * (selected.Time.HourEnd - firstHour)
+		if end >= rows {
+			offset = end - rows + 1
+		}
+		if start < offset {
+			offset = start
+		}
+	}
+	body := lines[gridHeaderLines+offset:]
+	if len(body) > rows {
+		body = body[:rows]
+	}
+	return append(lines[:gridHeaderLines:gridHeaderLines], body...)
+}
+
+// details returns the lines that describe the selected course.
+func (m *tuiModel) details(width int) []string {
+	course, ok := m.selected()
+	if !ok {
+		if len(m.courses(m.weekday)) == 0 {
+			return []string{fit(fmt.Sprintf("No courses on %s", m.weekday), width)}
+		}
+		return []string{fit("No course selected", width)}
+	}
+
+	return []string{
+		color.New(color.FgWhite, color.Bold).Sprint(fit(fmt.Sprintf("%s (%s)", course.NameLong, course.NameShort), width)),
+		fit(fmt.Sprintf("%s, %s", course.Lesson, course.Time), width),
+		fit(fmt.Sprintf("%s (%s)", course.ProfessorLong, course.ProfessorShort), width),
+		fit(course.Room, width),
+	}
+}
+
+// status returns the error of the last action, a loading message or help.
+func (m *tuiModel) status(width int, help string) string {
+	switch {
+	case m.err != nil:
+		return color.New(color.FgRed).Sprint(fit(m.err.Error(), width))
+	case m.loading:
+		return fit(fmt.Sprintf("Loading the timetable of %s...", m.program.ID), width)
+	default:
+		return color.New(color.Faint).Sprint(fit(help, width))
+	}
+}
+
+// formatProgram returns a single line that describes program.
+func formatProgram(program fbnd.DegreeProgram) string {
+	return fmt.Sprintf("%s %s, %s, %s %d, Semester %d",
+		program.ID, program.Name, program.Degree, program.Semester.Cycle, program.Semester.Year, program.Semester.Term)
+}
+
+// fit returns s truncated or padded with spaces to exactly width characters.
+func fit(s string, width int) string {
+	if width <= 0 {
+		return ""
+	}
+	s = truncate(s, width)
+	return s + strings.Repeat(" ", width-utf8.RuneCountInString(s))
+}
+
+func withoutLastRune(s string) string {
+	_, size := utf8.DecodeLastRuneInString(s)
+	return s[:len(s)-size]
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/n9v9/fbnd"
+)
+
+func TestParseKeys(t *testing.T) {
+	type testCase struct {
+		name  string
+		input string
+		want  []string
+	}
+
+	tests := []testCase{
+		{name: "characters", input: "aü/", want: []string{"a", "ü", "/"}},
+		{name: "arrows", input: "\x1b[A\x1b[B\x1bOC\x1b[D", want: []string{keyUp, keyDown, keyRight, keyLeft}},
+		{name: "escape", input: "\x1b", want: []string{keyEscape}},
+		{name: "control keys", input: "\r\x7f\x03", want: []string{keyEnter, keyBackspace, keyCtrlC}},
+		{name: "unknown sequence", input: "\x1b[3~x", want: []string{"x"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := parseKeys([]byte(test.input)); !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("want %q, got %q", test.want, got)
+			}
+		})
+	}
+}
+
+func newTestTUIModel(t *testing.T) *tuiModel {
+	t.Helper()
+
+	programs := []fbnd.DegreeProgram{
+		{ID: "BI1", Name: "Informatik", Degree: fbnd.Bachelor, Semester: fbnd.Semester{Cycle: fbnd.Winter, Year: 2022, Term: 1}},
+		{ID: "MI1", Name: "Informatik", Degree: fbnd.Master, Semester: fbnd.Semester{Cycle: fbnd.Winter, Year: 2022, Term: 1}},
+	}
+	m := newTUIModel(programs)
+	// A Monday at 9 o'clock.
+	m.now = time.Date(2022, 10, 10, 9, 0, 0, 0, fbnd.Location)
+
+	for _, key := range []string{"m", "i", keyEnter} {
+		m.update(key)
+	}
+	if m.program.ID != "MI1" || !m.loading {
+		t.Fatalf("want MI1 to be loaded, got %v", m.program.ID)
+	}
+
+	m.setTimetable(&fbnd.Timetable{Days: []fbnd.TimetableDay{
+		{Weekday: time.Monday, Courses: []fbnd.Course{
+			{NameLong: "Mathematik 1", NameShort: "MA1", ProfessorLong: "Prof. Dr. Müller", ProfessorShort: "Mül", Room: "B 1.10", Lesson: fbnd.Lecture, Time: fbnd.FB03Slots.Time(time.Monday, 8, 10)},
+			{NameLong: "Programmierung 1", NameShort: "PR1", ProfessorLong: "Prof. Dr. Schmidt", ProfessorShort: "Sch", Room: "Z 2.10", Lesson: fbnd.Exercise, Time: fbnd.FB03Slots.Time(time.Monday, 12, 14)},
+		}},
+		{Weekday: time.Wednesday, Courses: []fbnd.Course{
+			{NameLong: "Programmierung 1", NameShort: "PR1", ProfessorLong: "Prof. Dr. Schmidt", ProfessorShort: "Sch", Room: "Z 2.10", Lesson: fbnd.Lecture, Time: fbnd.FB03Slots.Time(time.Wednesday, 10, 12)},
+		}},
+	}}, nil)
+
+	return m
+}
+
+func TestTUIModelNavigation(t *testing.T) {
+	m := newTestTUIModel(t)
+
+	// The current course of today is selected.
+	if c, ok := m.selected(); !ok || c.NameShort != "MA1" {
+		t.Fatalf("want MA1 to be selected, got %v", c.NameShort)
+	}
+
+	type step struct {
+		key       string
+		weekday   time.Weekday
+		nameShort string
+	}
+
+	steps := []step{
+		{key: keyDown, weekday: time.Monday, nameShort: "PR1"},
+		{key: keyDown, weekday: time.Monday, nameShort: "PR1"},
+		{key: "l", weekday: time.Tuesday, nameShort: ""},
+		{key: keyRight, weekday: time.Wednesday, nameShort: "PR1"},
+		{key: "h", weekday: time.Tuesday, nameShort: ""},
+		{key: keyLeft, weekday: time.Monday, nameShort: "MA1"},
+		{key: keyLeft, weekday: time.Monday, nameShort: "MA1"},
+	}
+
+	for i, s := range steps {
+		m.update(s.key)
+		c, _ := m.selected()
+		if m.weekday != s.weekday || c.NameShort != s.nameShort {
+			t.Fatalf("step %d: want %v %q, got %v %q", i, s.weekday, s.nameShort, m.weekday, c.NameShort)
+		}
+	}
+
+	if m.update(keyEscape); m.timetable != nil {
+		t.Fatal("want the degree programs to be shown")
+	}
+	if action := m.update(keyEscape); action != tuiNone || m.filter != "" {
+		t.Fatalf("want the search to be cleared, got %v %q", action, m.filter)
+	}
+	if action := m.update(keyEscape); action != tuiQuit {
+		t.Fatalf("want %v, got %v", tuiQuit, action)
+	}
+}
+
+func TestTUIModelSearch(t *testing.T) {
+	m := newTestTUIModel(t)
+
+	for _, key := range []string{"/", "s", "c", "h", "m", "i", "d", "t"} {
+		m.update(key)
+	}
+	if !m.searching || m.query != "schmidt" {
+		t.Fatalf("want search for schmidt, got %v %q", m.searching, m.query)
+	}
+
+	// MA1 does not match, so the matching course of the day is selected.
+	if c, ok := m.selected(); !ok || c.NameShort != "PR1" || c.Lesson != fbnd.Exercise {
+		t.Fatalf("want the exercise PR1 to be selected, got %v", c)
+	}
+	if m.update(keyUp); m.course != 1 {
+		t.Fatalf("want the selection to skip MA1, got %d", m.course)
+	}
+
+	// Leaving the search keeps the query, escape clears it.
+	if m.update(keyEnter); m.searching || m.query != "schmidt" {
+		t.Fatalf("want the query to be kept, got %v %q", m.searching, m.query)
+	}
+	if m.update("q") != tuiQuit {
+		t.Fatal("want q to quit outside of the search")
+	}
+	if m.update(keyEscape); m.query != "" || m.matches != nil || m.timetable == nil {
+		t.Fatalf("want the query to be cleared, got %q", m.query)
+	}
+}
+
+func TestTUIModelView(t *testing.T) {
+	defer func(noColor bool) { color.NoColor = noColor }(color.NoColor)
+	color.NoColor = true
+	m := newTestTUIModel(t)
+
+	lines := m.view(80, 24)
+	if len(lines) != 24 {
+		t.Fatalf("want 24 lines, got %d", len(lines))
+	}
+
+	screen := strings.Join(lines, "\n")
+	for _, want := range []string{
+		"MI1 Informatik, Master, Winter 2022, Semester 1",
+		"Monday",
+		">MA1",
+		"Mathematik 1 (MA1)",
+		"Prof. Dr. Müller (Mül)",
+	} {
+		if !strings.Contains(screen, want) {
+			t.Fatalf("want screen to contain %q, got\n%s", want, screen)
+		}
+	}
+	for i, line := range lines {
+		if n := len([]rune(line)); n > 80 {
+			t.Fatalf("want line %d to fit into 80 columns, got %d", i, n)
+		}
+	}
+
+	// On a smaller screen, the hours are scrolled to the selected course.
+	m.update(keyDown)
+	screen = strings.Join(m.view(80, 16), "\n")
+	if !strings.Contains(screen, ">PR1") || strings.Contains(screen, "MA1 V") {
+		t.Fatalf("want the grid to be scrolled to PR1, got\n%s", screen)
+	}
+}
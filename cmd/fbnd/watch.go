@@ -0,0 +1,244 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/n9v9/fbnd"
+	"github.com/spf13/cobra"
+)
+
+func cmdWatch() *cobra.Command {
+	var (
+		interval time.Duration
+		once     bool
+		w        = watcher{logger: log.New(os.Stderr, "", log.LstdFlags), out: color.Output}
+	)
+
+	cmd := &cobra.Command{
+		Use:   "watch <ID>...",
+		Short: "Watch the timetables of degree programs for changes",
+		Long: `Watch the timetables of degree programs for changes
+
+The timetables are fetched periodically and compared with the last fetched ones,
+which are stored on disk. Changes are printed and passed to the hooks:
+
+  --exec     Runs the command with the shell. The changes are passed as JSON on stdin
+             and the ID of the degree program in the environment variable FBND_ID.
+  --webhook  Sends the changes as JSON in a POST request to the URL.
+
+The JSON has the form {"id": "...", "time": "...", "diff": {...}}, where diff is
+the same as the output of the diff command with the --json flag.
+The first time a timetable is fetched, it is only stored.`,
+		Args:   cobra.MinimumNArgs(1),
+		PreRun: initClient,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := runWatch(cmd.Context(), &w, args, interval, once); err != nil {
+				printError(err)
+				os.Exit(1)
+			}
+		},
+	}
+
+	cmd.Flags().DurationVar(&interval, "interval", 15*time.Minute, "Duration between two checks for changes")
+	cmd.Flags().BoolVar(&once, "once", false, "Check for changes one time and exit, for example to run it periodically yourself")
+	cmd.Flags().StringVar(&w.command, "exec", "", "Shell command to run when a timetable changed")
+	cmd.Flags().StringVar(&w.webhook, "webhook", "", "URL to which changes are sent in a POST request")
+	cmd.Flags().StringVar(&w.dir, "state-dir", "", "Directory in which the last timetables are stored (default \"<config dir>/fbnd/watch\")")
+
+	return cmd
+}
+
+func runWatch(ctx context.Context, w *watcher, ids []string, interval time.Duration, once bool) error {
+	if w.dir == "" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return fmt.Errorf("could not determine config directory: %w", err)
+		}
+		w.dir = filepath.Join(dir, "fbnd", "watch")
+	}
+
+	// Cached responses would hide changes, so fresh data is always fetched.
+	w.client = client
+	if !noCache && !offline {
+		c, err := newClient(fbnd.CacheRefresh)
+		if err != nil {
+			return err
+		}
+		w.client = c
+	}
+	w.httpClient = &http.Client{Timeout: timeout}
+
+	for {
+		for _, id := range ids {
+			if err := w.check(ctx, fbnd.ID(strings.ToUpper(id))); err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				if once {
+					return err
+				}
+				// Keep watching, the next check may succeed.
+				w.logger.Printf("could not check %s for changes: %v", id, err)
+			}
+		}
+
+		if once {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(interval):
+		}
+	}
+}
+
+// watchEvent is passed to the hooks when a timetable changed.
+type watchEvent struct {
+	ID   fbnd.ID            `json:"id"`
+	Time time.Time          `json:"time"`
+	Diff fbnd.TimetableDiff `json:"diff"`
+}
+
+// watcher compares timetables with their last snapshot stored in dir and
+// notifies the hooks about changes.
+type watcher struct {
+	client     *fbnd.Client
+	httpClient *http.Client
+	dir        string
+	command    string
+	webhook    string
+	logger     *log.Logger
+	out        io.Writer
+}
+
+// check fetches the timetable of the degree program with the given ID, compares it
+// with its snapshot and notifies the hooks if it changed.
+func (w *watcher) check(ctx context.Context, id fbnd.ID) error {
+	timetable, err := w.client.TimetableForDegreeProgram(ctx, id)
+	if err != nil {
+		return err
+	}
+	if len(timetable.Days) == 0 {
+		return fmt.Errorf("could find no courses for degree program with id %s", id)
+	}
+
+	path := filepath.Join(w.dir, string(id)+".json")
+	old, err := readTimetable(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		w.logger.Printf("storing the first snapshot of %s", id)
+		return writeTimetable(path, timetable)
+	}
+	if err != nil {
+		return err
+	}
+
+	diff := fbnd.Diff(old, timetable)
+	if diff.Empty() {
+		return nil
+	}
+
+	fmt.Fprintf(w.out, "%s changed:\n", id)
+	printDiff(w.out, diff)
+
+	if err := w.notify(ctx, watchEvent{ID: id, Time: time.Now(), Diff: diff}); err != nil {
+		// Keep the old snapshot, so that the hooks are notified again.
+		return err
+	}
+
+	return writeTimetable(path, timetable)
+}
+
+// notify runs the command and sends the event to the webhook, if they are configured.
+func (w *watcher) notify(ctx context.Context, event watchEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	if w.command != "" {
+		var cmd *exec.Cmd
+		if runtime.GOOS == "windows" {
+			cmd = exec.CommandContext(ctx, "cmd", "/C", w.command)
+		} else {
+			cmd = exec.CommandContext(ctx, "sh", "-c", w.command)
+		}
+		cmd.Env = append(os.Environ(), "FBND_ID="+string(event.ID))
+		cmd.Stdin = bytes.NewReader(payload)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("could not run command: %w", err)
+		}
+	}
+
+	if w.webhook != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.webhook, bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := w.httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("could not call webhook: %w", err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode > 299 {
+			return fmt.Errorf("could not call webhook: unexpected status %s", resp.Status)
+		}
+	}
+
+	return nil
+}
+
+// writeTimetable writes timetable in JSON format to the file at path.
+func writeTimetable(path string, timetable *fbnd.Timetable) error {
+	data, err := json.MarshalIndent(timetable, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+// writeFileAtomic writes data to the file at path, creating its directory if needed.
+// The data is written to a temporary file first, so that readers never see a partial file.
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/n9v9/fbnd"
+	"github.com/n9v9/fbnd/fbndtest"
+)
+
+func TestWatcherCheck(t *testing.T) {
+	courses := fbndtest.SampleCourses()[:1]
+	upstream := fbndtest.NewServer(fbndtest.SamplePrograms(), map[fbnd.ID][]fbnd.Course{"BI1": courses})
+	defer upstream.Close()
+
+	var events []watchEvent
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event watchEvent
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Error(err)
+		}
+		events = append(events, event)
+	}))
+	defer webhook.Close()
+
+	w := &watcher{
+		client:     upstream.Client(),
+		httpClient: webhook.Client(),
+		dir:        t.TempDir(),
+		webhook:    webhook.URL,
+		logger:     log.New(io.Discard, "", 0),
+		out:        io.Discard,
+	}
+	ctx := context.Background()
+
+	// The first check only stores the snapshot, the second one finds no changes.
+	for i := 0; i < 2; i++ {
+		if err := w.check(ctx, "BI1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(events) != 0 {
+		t.Fatalf("want no events, got %v", events)
+	}
+
+	courses[0].Room = "B 2.10"
+	upstream.SetTimetable("BI1", courses)
+	for i := 0; i < 2; i++ {
+		if err := w.check(ctx, "BI1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(events) != 1 {
+		t.Fatalf("want 1 event, got %v", events)
+	}
+	want := []fbnd.FieldChange{{Field: fbnd.FieldRoom, Old: "B 1.10", New: "B 2.10"}}
+	if got := events[0].Diff.Modified; events[0].ID != "BI1" || len(got) != 1 || len(got[0].Changes) != 1 || got[0].Changes[0] != want[0] {
+		t.Fatalf("want event for BI1 with changes %v, got %+v", want, events[0])
+	}
+}
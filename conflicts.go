@@ -0,0 +1,55 @@
+package fbnd
+
+import "sort"
+
+// Conflict is a pair of courses that take place on the same weekday at the same time.
+// A starts before B, or at the same hour.
+type Conflict struct {
+	A Course `json:"a"`
+	B Course `json:"b"`
+}
+
+// Conflicts returns all pairs of courses that take place on the same weekday and whose
+// hours overlap, using HourStart and HourEnd. A course that is contained multiple
+// times, for example because it is part of several timetables, does not conflict with
+// itself. The conflicts are sorted by the weekday and start of A.
+func Conflicts(courses []Course) []Conflict {
+	seen := make(map[Course]bool)
+	var unique []Course
+	for _, v := range courses {
+		if !seen[v] {
+			seen[v] = true
+			unique = append(unique, v)
+		}
+	}
+
+	sort.SliceStable(unique, func(i, j int) bool {
+		a, b := unique[i].Time, unique[j].Time
+		if a.Weekday != b.Weekday {
+			return a.Weekday < b.Weekday
+		}
+		if a.HourStart != b.HourStart {
+			return a.HourStart < b.HourStart
+		}
+		return unique[i].NameShort < unique[j].NameShort
+	})
+
+	var conflicts []Conflict
+	for i, a := range unique {
+		for _, b := range unique[i+1:] {
+			if !hoursOverlap(a.Time, b.Time) {
+				// The courses are sorted, so no later course overlaps a.
+				break
+			}
+			conflicts = append(conflicts, Conflict{A: a, B: b})
+		}
+	}
+
+	return conflicts
+}
+
+// hoursOverlap reports whether a and b take place on the same weekday and their hours
+// of the timetable overlap.
+func hoursOverlap(a, b Time) bool {
+	return a.Weekday == b.Weekday && a.HourStart < b.HourEnd && b.HourStart < a.HourEnd
+}
@@ -0,0 +1,48 @@
+package fbnd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestConflicts(t *testing.T) {
+	ma1 := Course{NameShort: "MA1", Lesson: Lecture, Time: FB03Slots.Time(time.Monday, 8, 10)}
+	pr1 := Course{NameShort: "PR1", Lesson: Exercise, Time: FB03Slots.Time(time.Monday, 9, 11)}
+	db := Course{NameShort: "DB", Lesson: Lecture, Time: FB03Slots.Time(time.Monday, 10, 12)}
+	se := Course{NameShort: "SE", Lesson: Lecture, Time: FB03Slots.Time(time.Tuesday, 8, 10)}
+	ml := Course{NameShort: "ML", Lesson: Lecture, Time: FB03Slots.Time(time.Monday, 8, 14)}
+
+	type testCase struct {
+		name    string
+		courses []Course
+		want    []Conflict
+	}
+
+	tests := []testCase{
+		{name: "no courses", courses: nil, want: nil},
+		{name: "adjacent", courses: []Course{ma1, db}, want: nil},
+		{name: "other weekday", courses: []Course{ma1, se}, want: nil},
+		{name: "same course twice", courses: []Course{ma1, ma1}, want: nil},
+		{name: "overlap", courses: []Course{pr1, ma1}, want: []Conflict{{A: ma1, B: pr1}}},
+		{
+			name:    "multiple overlaps",
+			courses: []Course{db, se, ma1, pr1, ml},
+			want: []Conflict{
+				{A: ma1, B: ml},
+				{A: ma1, B: pr1},
+				{A: ml, B: pr1},
+				{A: ml, B: db},
+				{A: pr1, B: db},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Conflicts(test.courses); !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("want %v, got %v", test.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package fbnd
+
+// CourseField names a field of a Course whose changes are reported by Diff.
+type CourseField string
+
+const (
+	FieldRoom           CourseField = "room"
+	FieldProfessorShort CourseField = "professorShort"
+	FieldTime           CourseField = "time"
+)
+
+// FieldChange is the change of a single field of a Course.
+type FieldChange struct {
+	Field CourseField `json:"field"`
+	Old   string      `json:"old"`
+	New   string      `json:"new"`
+}
+
+// Modification is a Course that exists in both timetables compared by Diff, but differs.
+type Modification struct {
+	Old Course `json:"old"`
+	New Course `json:"new"`
+	// Changes are the changes of the fields Room, ProfessorShort and Time.
+	// It is empty if only other fields, like NameLong, changed.
+	Changes []FieldChange `json:"changes"`
+}
+
+// TimetableDiff is the result of Diff.
+type TimetableDiff struct {
+	Added    []Course       `json:"added"`
+	Removed  []Course       `json:"removed"`
+	Modified []Modification `json:"modified"`
+}
+
+// Empty reports whether there are no differences.
+func (d TimetableDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Modified) == 0
+}
+
+// courseKey identifies a course across two timetables, regardless of where and when it takes place.
+type courseKey struct {
+	nameShort string
+	lesson    Lesson
+}
+
+// Diff compares the courses of old and new, either of which can be nil.
+//
+// Courses are matched by their NameShort and Lesson. If the same course takes place
+// multiple times, courses are matched such that as few fields as possible differ,
+// preferring courses that only moved to a different room or changed the professor.
+// Matched courses that differ are reported as modified, all other courses as added
+// or removed.
+func Diff(old, new *Timetable) TimetableDiff {
+	oldCourses := unmatched(old)
+	newCourses := unmatched(new)
+
+	// The slices are never nil, so that they are encoded as empty arrays in JSON.
+	diff := TimetableDiff{Modified: []Modification{}}
+
+	// First remove all identical courses, then match the ones that differ in
+	// one, two and finally all three compared fields.
+	for differences := 0; differences <= 3; differences++ {
+		for i := 0; i < len(oldCourses); i++ {
+			o := oldCourses[i]
+			for j := 0; j < len(newCourses); j++ {
+				n := newCourses[j]
+				if (courseKey{o.NameShort, o.Lesson}) != (courseKey{n.NameShort, n.Lesson}) {
+					continue
+				}
+
+				changes := fieldChanges(o, n)
+				if len(changes) != differences {
+					continue
+				}
+				if differences > 0 || o != n {
+					diff.Modified = append(diff.Modified, Modification{Old: o, New: n, Changes: changes})
+				}
+
+				oldCourses = append(oldCourses[:i], oldCourses[i+1:]...)
+				newCourses = append(newCourses[:j], newCourses[j+1:]...)
+				i--
+				break
+			}
+		}
+	}
+
+	diff.Removed = append([]Course{}, oldCourses...)
+	diff.Added = append([]Course{}, newCourses...)
+
+	return diff
+}
+
+// unmatched returns a copy of all courses of t. Courses without clock times get the
+// full hours as clock times, so that times are compared the way Time.String shows them.
+func unmatched(t *Timetable) []Course {
+	if t == nil {
+		return nil
+	}
+
+	var courses []Course
+	for _, day := range t.Days {
+		for _, course := range day.Courses {
+			course.Time.Start, course.Time.End = course.Time.clocks()
+			courses = append(courses, course)
+		}
+	}
+	return courses
+}
+
+// fieldChanges returns the changes of the compared fields from old to new.
+func fieldChanges(old, new Course) []FieldChange {
+	var changes []FieldChange
+
+	if old.Room != new.Room {
+		changes = append(changes, FieldChange{Field: FieldRoom, Old: old.Room, New: new.Room})
+	}
+	if old.ProfessorShort != new.ProfessorShort {
+		changes = append(changes, FieldChange{Field: FieldProfessorShort, Old: old.ProfessorShort, New: new.ProfessorShort})
+	}
+	if old.Time != new.Time {
+		changes = append(changes, FieldChange{Field: FieldTime, Old: old.Time.String(), New: new.Time.String()})
+	}
+
+	return changes
+}
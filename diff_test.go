@@ -0,0 +1,93 @@
+package fbnd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiff(t *testing.T) {
+	ma1 := Course{NameShort: "MA1", ProfessorShort: "Mül", Room: "B 1.10", Lesson: Lecture, Time: FB03Slots.Time(time.Monday, 8, 10)}
+	ma1Thursday := Course{NameShort: "MA1", ProfessorShort: "Mül", Room: "B 1.10", Lesson: Lecture, Time: FB03Slots.Time(time.Thursday, 8, 10)}
+	pr1 := Course{NameShort: "PR1", ProfessorShort: "Sch", Room: "Z 2.10", Lesson: Exercise, Time: FB03Slots.Time(time.Tuesday, 10, 12)}
+	en := Course{NameShort: "EN", ProfessorShort: "Smi", Room: "B 2.01", Lesson: LanguageLecture, Time: FB03Slots.Time(time.Friday, 14, 16)}
+
+	timetable := func(courses ...Course) *Timetable {
+		t := &Timetable{}
+		for _, v := range courses {
+			t.Days = append(t.Days, TimetableDay{Weekday: v.Time.Weekday, Courses: []Course{v}})
+		}
+		return t
+	}
+	with := func(c Course, modify func(c *Course)) Course {
+		modify(&c)
+		return c
+	}
+
+	movedMA1 := with(ma1Thursday, func(c *Course) { c.Room = "B 3.10" })
+	ma1Friday := with(ma1Thursday, func(c *Course) { c.Time = FB03Slots.Time(time.Friday, 10, 12) })
+	pr1NewProfessor := with(pr1, func(c *Course) { c.ProfessorShort = "Web" })
+
+	type testCase struct {
+		name string
+		old  *Timetable
+		new  *Timetable
+		want TimetableDiff
+	}
+
+	testCases := []testCase{
+		{
+			name: "Identical",
+			old:  timetable(ma1, pr1),
+			new:  timetable(ma1, pr1),
+			want: TimetableDiff{},
+		},
+		{
+			name: "AddedAndRemoved",
+			old:  timetable(ma1, en),
+			new:  timetable(ma1, pr1),
+			want: TimetableDiff{Added: []Course{pr1}, Removed: []Course{en}},
+		},
+		{
+			name: "Nil",
+			old:  nil,
+			new:  timetable(ma1),
+			want: TimetableDiff{Added: []Course{ma1}},
+		},
+		{
+			name: "ModifiedFields",
+			old:  timetable(ma1, ma1Thursday, pr1),
+			new:  timetable(ma1, movedMA1, pr1NewProfessor),
+			want: TimetableDiff{Modified: []Modification{
+				{Old: ma1Thursday, New: movedMA1, Changes: []FieldChange{{Field: FieldRoom, Old: "B 1.10", New: "B 3.10"}}},
+				{Old: pr1, New: pr1NewProfessor, Changes: []FieldChange{{Field: FieldProfessorShort, Old: "Sch", New: "Web"}}},
+			}},
+		},
+		{
+			name: "MovedCourseOfMany",
+			old:  timetable(ma1, ma1Thursday),
+			new:  timetable(ma1Friday, ma1),
+			want: TimetableDiff{Modified: []Modification{
+				{Old: ma1Thursday, New: ma1Friday, Changes: []FieldChange{{Field: FieldTime, Old: "Thursday 08:15 - 09:45", New: "Friday 10:00 - 11:30"}}},
+			}},
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			got := Diff(test.old, test.new)
+			if got.Added == nil || got.Removed == nil || got.Modified == nil {
+				t.Fatalf("want empty slices instead of nil, got %#v", got)
+			}
+			// Compare empty slices like nil ones.
+			if !reflect.DeepEqual(append([]Course(nil), got.Added...), test.want.Added) ||
+				!reflect.DeepEqual(append([]Course(nil), got.Removed...), test.want.Removed) ||
+				!reflect.DeepEqual(append([]Modification(nil), got.Modified...), test.want.Modified) {
+				t.Fatalf("want %+v, got %+v", test.want, got)
+			}
+			if got.Empty() != test.want.Empty() {
+				t.Fatalf("want empty %v, got %v", test.want.Empty(), got.Empty())
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package fbnd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// ErrStructureChanged is returned, possibly wrapped, when the website does not have
+// the expected structure. This usually means that the website changed and this
+// package needs to be updated.
+var ErrStructureChanged = errors.New("the structure of the website changed")
+
+// ParseError describes a part of the website that could not be parsed.
+// It matches ErrStructureChanged when used with errors.Is.
+type ParseError struct {
+	// Selector is the CSS selector of the element that was parsed.
+	Selector string
+	// Field is the name of the value that could not be parsed, e.g. "semester term".
+	Field string
+	// Snippet is the text or attribute of the element that could not be parsed.
+	// Long snippets are truncated.
+	Snippet string
+	// Err is the underlying error, if any.
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	msg := fmt.Sprintf("could not parse %s from %q (selector %s)", e.Field, e.Snippet, e.Selector)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap returns the underlying error.
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is ErrStructureChanged.
+func (e *ParseError) Is(target error) bool {
+	return target == ErrStructureChanged
+}
+
+// maxSnippetLength is the maximum length of ParseError.Snippet.
+const maxSnippetLength = 120
+
+// newParseError returns a ParseError with a snippet that is shortened to maxSnippetLength.
+func newParseError(selector, field, snippet string, err error) *ParseError {
+	snippet = strings.Join(strings.Fields(snippet), " ")
+	if runes := []rune(snippet); len(runes) > maxSnippetLength {
+		snippet = string(runes[:maxSnippetLength]) + "..."
+	}
+
+	return &ParseError{
+		Selector: selector,
+		Field:    field,
+		Snippet:  snippet,
+		Err:      err,
+	}
+}
+
+// outerHTML returns the HTML of s including s itself, or an empty string if it
+// could not be rendered.
+func outerHTML(s *goquery.Selection) string {
+	html, err := goquery.OuterHtml(s)
+	if err != nil {
+		return ""
+	}
+	return html
+}
@@ -0,0 +1,79 @@
+package fbnd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+func TestParseErrors(t *testing.T) {
+	type testCase struct {
+		name  string
+		html  string
+		parse func(doc *goquery.Document) error
+		field string
+	}
+
+	testCases := []testCase{
+		{
+			name: "NoSemesterChecked",
+			html: `<input id="inlineWintersemester"><label for="inlineWintersemester">Wintersemester 2022</label>`,
+			parse: func(doc *goquery.Document) error {
+				_, _, err := parseSemesterYear(doc)
+				return err
+			},
+			field: "checked semester",
+		},
+		{
+			name: "MalformedSemesterYear",
+			html: `<input id="inlineWintersemester" checked><label for="inlineWintersemester">Winter 22</label>`,
+			parse: func(doc *goquery.Document) error {
+				_, _, err := parseSemesterYear(doc)
+				return err
+			},
+			field: "semester year",
+		},
+		{
+			name: "MalformedHours",
+			html: `<table><thead><tr><th></th><th>8</th></tr></thead></table>`,
+			parse: func(doc *goquery.Document) error {
+				_, err := parseHours(doc)
+				return err
+			},
+			field: "hours",
+		},
+		{
+			name: "MalformedDegreeProgram",
+			html: `<select id="select_S"><optgroup label="Bachelor"><option value="BI1">Informatik</option></optgroup></select>`,
+			parse: func(doc *goquery.Document) error {
+				_, err := parseDegreeProgramNames(doc, Winter, 2022)
+				return err
+			},
+			field: "degree program name",
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			doc, err := goquery.NewDocumentFromReader(strings.NewReader(test.html))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			err = test.parse(doc)
+			if !errors.Is(err, ErrStructureChanged) {
+				t.Fatalf("want error matching %v, got %v", ErrStructureChanged, err)
+			}
+
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("want *ParseError, got %T", err)
+			}
+			if parseErr.Field != test.field {
+				t.Fatalf("want field %q, got %q", test.field, parseErr.Field)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package fbnd_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/n9v9/fbnd"
+	"github.com/n9v9/fbnd/fbndtest"
+)
+
+func newTestServer(t *testing.T) *fbndtest.Server {
+	t.Helper()
+
+	server := fbndtest.NewSampleServer()
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestDegreePrograms(t *testing.T) {
+	client := newTestServer(t).Client()
+
+	for _, cycle := range []fbnd.SemesterCycle{fbnd.Winter, fbnd.Summer} {
+		got, err := client.DegreePrograms(context.Background(), cycle)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var want []fbnd.DegreeProgram
+		for _, v := range fbndtest.SamplePrograms() {
+			if v.Semester.Cycle == cycle {
+				want = append(want, v)
+			}
+		}
+
+		if !reflect.DeepEqual(want, got) {
+			t.Fatalf("want %v, got %v", want, got)
+		}
+	}
+}
+
+func TestTimetableForDegreeProgram(t *testing.T) {
+	client := newTestServer(t).Client()
+
+	timetable, err := client.TimetableForDegreeProgram(context.Background(), "bi1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if timetable.DegreeProgram == nil || *timetable.DegreeProgram != fbndtest.SamplePrograms()[0] {
+		t.Fatalf("want degree program %v, got %v", fbndtest.SamplePrograms()[0], timetable.DegreeProgram)
+	}
+
+	want := []fbnd.TimetableDay{
+		{Weekday: time.Monday, Courses: fbndtest.SampleCourses()[:2]},
+		{Weekday: time.Wednesday, Courses: fbndtest.SampleCourses()[2:]},
+	}
+	if !reflect.DeepEqual(want, timetable.Days) {
+		t.Fatalf("want days %v, got %v", want, timetable.Days)
+	}
+}
+
+func TestTimetableForUnknownDegreeProgram(t *testing.T) {
+	client := newTestServer(t).Client()
+
+	timetable, err := client.TimetableForDegreeProgram(context.Background(), "XX")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(timetable.Days) != 0 {
+		t.Fatalf("want no days, got %v", timetable.Days)
+	}
+}
+
+func TestFillDegreeProgram(t *testing.T) {
+	server := newTestServer(t)
+	client := server.Client()
+
+	timetable, err := client.TimetableForDegreeProgram(context.Background(), "BI2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if timetable.DegreeProgram != nil {
+		t.Fatalf("want no degree program for a summer semester, got %v", timetable.DegreeProgram)
+	}
+
+	if err := timetable.FillDegreeProgramContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if *timetable.DegreeProgram != fbndtest.SamplePrograms()[2] {
+		t.Fatalf("want degree program %v, got %v", fbndtest.SamplePrograms()[2], *timetable.DegreeProgram)
+	}
+
+	// The degree program is already known, so no further request must be made.
+	requests := server.Requests()
+	if err := timetable.FillDegreeProgram(); err != nil {
+		t.Fatal(err)
+	}
+	if server.Requests() != requests {
+		t.Fatalf("want %d requests, got %d", requests, server.Requests())
+	}
+}
+
+func TestPackageLevelFunctions(t *testing.T) {
+	defaultClient := fbnd.DefaultClient
+	fbnd.DefaultClient = newTestServer(t).Client()
+	t.Cleanup(func() { fbnd.DefaultClient = defaultClient })
+
+	programs, err := fbnd.DegreePrograms(fbnd.Summer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(programs) != 1 {
+		t.Fatalf("want 1 degree program, got %d", len(programs))
+	}
+
+	timetable, err := fbnd.TimetableForDegreeProgram(programs[0].ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(timetable.Days) != 1 {
+		t.Fatalf("want 1 day, got %d", len(timetable.Days))
+	}
+}
+
+func TestTimetableParallelCourses(t *testing.T) {
+	server := newTestServer(t)
+
+	parallel := []fbnd.Course{
+		{NameLong: "Mathematik 1", NameShort: "MA1", ProfessorLong: "Prof. Dr. Müller", ProfessorShort: "Mül", Room: "H 1.01", Lesson: fbnd.Lecture, Time: fbnd.FB03Slots.Time(time.Monday, 8, 10)},
+		{NameLong: "Mathematik 1 Gruppe A", NameShort: "MA1-A", ProfessorLong: "Prof. Dr. Müller", ProfessorShort: "Mül", Room: "R 0.05", Lesson: fbnd.Exercise, Time: fbnd.FB03Slots.Time(time.Monday, 10, 12)},
+		{NameLong: "Mathematik 1 Gruppe B", NameShort: "MA1-B", ProfessorLong: "Dipl.-Math. Lange", ProfessorShort: "Lan", Room: "R 0.07", Lesson: fbnd.Exercise, Time: fbnd.FB03Slots.Time(time.Monday, 10, 12)},
+		{NameLong: "Tutorium", NameShort: "TUT", ProfessorLong: "B. Sc. Krüger", ProfessorShort: "Krü", Room: "R 0.05", Lesson: fbnd.Tutorial, Time: fbnd.FB03Slots.Time(time.Monday, 11, 14)},
+	}
+	server.SetTimetable("BI1", parallel)
+
+	timetable, err := server.Client().TimetableForDegreeProgram(context.Background(), "BI1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []fbnd.TimetableDay{{Weekday: time.Monday, Courses: parallel}}
+	if !reflect.DeepEqual(want, timetable.Days) {
+		t.Fatalf("want days %v, got %v", want, timetable.Days)
+	}
+}
+
+func TestClientWithCalendar(t *testing.T) {
+	server := newTestServer(t)
+	calendar := fbnd.SemesterCalendar{
+		Lectures: fbnd.Period{Start: fbnd.Date(2022, time.March, 14), End: fbnd.Date(2022, time.July, 15)},
+		Breaks:   []fbnd.Period{{Start: fbnd.Date(2022, time.April, 11), End: fbnd.Date(2022, time.April, 22)}},
+	}
+	client := server.Client(fbnd.WithCalendar(fbnd.Summer, 2022, calendar))
+
+	timetable, err := client.TimetableForDegreeProgram(context.Background(), "BI2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := timetable.FillDegreeProgramContext(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := timetable.DegreeProgram.Semester.Calendar; got == nil || !reflect.DeepEqual(*got, calendar) {
+		t.Fatalf("want calendar %v, got %v", calendar, got)
+	}
+
+	// The calendar is only set for the configured semester.
+	programs, err := client.DegreePrograms(context.Background(), fbnd.Winter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range programs {
+		if v.Semester.Calendar != nil {
+			t.Fatalf("want no calendar for degree program %s, got %v", v.ID, *v.Semester.Calendar)
+		}
+	}
+}
+
+func TestCourseGroup(t *testing.T) {
+	type testCase struct {
+		nameShort string
+		wantName  string
+		wantGroup string
+	}
+
+	testCases := []testCase{
+		{nameShort: "MA1-A", wantName: "MA1", wantGroup: "A"},
+		{nameShort: "PR1-12", wantName: "PR1", wantGroup: "12"},
+		{nameShort: "MA1", wantName: "MA1", wantGroup: ""},
+		{nameShort: "E-Technik", wantName: "E-Technik", wantGroup: ""},
+		{nameShort: "-A", wantName: "-A", wantGroup: ""},
+		{nameShort: "MA1-", wantName: "MA1-", wantGroup: ""},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.nameShort, func(t *testing.T) {
+			name, group := fbnd.Course{NameShort: test.nameShort}.Group()
+			if name != test.wantName || group != test.wantGroup {
+				t.Fatalf("want %q and %q, got %q and %q", test.wantName, test.wantGroup, name, group)
+			}
+		})
+	}
+}
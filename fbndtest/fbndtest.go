@@ -0,0 +1,316 @@
+// Package fbndtest provides a stand-in for the timetable website of FB03,
+// so that code using the fbnd package can be tested without network access.
+package fbndtest
+
+import (
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/n9v9/fbnd"
+)
+
+// Server emulates the stundenplan.php page of the timetable website.
+// It answers the same form posts that the fbnd package sends:
+//
+//   - `fkt=SR&Lage=<SS|WS>` returns the degree programs of the semester.
+//   - `fkt=SR&SR=<ID>&mode=SR` returns the timetable of the degree program.
+//     Like the real website, the page always lists the degree programs of
+//     the winter semester, regardless of the semester the ID belongs to.
+type Server struct {
+	*httptest.Server
+
+	mu         sync.Mutex
+	programs   []fbnd.DegreeProgram
+	timetables map[fbnd.ID][]fbnd.Course
+	requests   int
+}
+
+// NewServer starts and returns a new Server that serves the given programs and
+// the courses of their timetables, keyed by the ID of each degree program.
+// The caller should call Close when finished, to shut it down.
+func NewServer(programs []fbnd.DegreeProgram, timetables map[fbnd.ID][]fbnd.Course) *Server {
+	s := &Server{
+		programs:   programs,
+		timetables: make(map[fbnd.ID][]fbnd.Course, len(timetables)),
+	}
+	// Copy the timetables, so that SetTimetable does not modify the map of the caller.
+	for id, courses := range timetables {
+		s.timetables[id] = courses
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Client returns an fbnd.Client that sends its requests to s.
+// The options are applied after the ones that point the client at s.
+func (s *Server) Client(opts ...fbnd.Option) *fbnd.Client {
+	opts = append([]fbnd.Option{
+		fbnd.WithBaseURL(s.URL),
+		fbnd.WithHTTPClient(s.Server.Client()),
+	}, opts...)
+	return fbnd.NewClient(opts...)
+}
+
+// SetTimetable replaces the courses of the degree program with the given ID.
+func (s *Server) SetTimetable(id fbnd.ID, courses []fbnd.Course) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.timetables[id] = courses
+}
+
+// Requests returns the number of requests that s has answered.
+func (s *Server) Requests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests++
+
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if r.PostForm.Get("fkt") != "SR" {
+		http.Error(w, "unknown function", http.StatusBadRequest)
+		return
+	}
+
+	// The website defaults to the winter semester.
+	page := page{Cycle: fbnd.Winter}
+	if r.PostForm.Get("Lage") == "SS" {
+		page.Cycle = fbnd.Summer
+	}
+	page.Year = s.year(page.Cycle)
+	page.Bachelor, page.Master = s.programsFor(page.Cycle)
+
+	if r.PostForm.Get("mode") == "SR" {
+		id := fbnd.ID(strings.ToUpper(r.PostForm.Get("SR")))
+		page.Hours, page.Days = renderTimetable(s.timetables[id])
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := pageTemplate.Execute(w, page); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// year returns the year of the first degree program of the cycle,
+// or the current year if there is none.
+func (s *Server) year(cycle fbnd.SemesterCycle) int {
+	for _, v := range s.programs {
+		if v.Semester.Cycle == cycle {
+			return v.Semester.Year
+		}
+	}
+	return time.Now().Year()
+}
+
+func (s *Server) programsFor(cycle fbnd.SemesterCycle) (bachelor, master []fbnd.DegreeProgram) {
+	for _, v := range s.programs {
+		if v.Semester.Cycle != cycle {
+			continue
+		}
+		if v.Degree == fbnd.Master {
+			master = append(master, v)
+		} else {
+			bachelor = append(bachelor, v)
+		}
+	}
+	return bachelor, master
+}
+
+type page struct {
+	Cycle    fbnd.SemesterCycle
+	Year     int
+	Bachelor []fbnd.DegreeProgram
+	Master   []fbnd.DegreeProgram
+	Hours    []int
+	Days     [][]row
+}
+
+type row struct {
+	// Weekday is empty for all but the first row of a weekday.
+	Weekday string
+	Rowspan int
+	Cells   []cell
+}
+
+type cell struct {
+	Span   int
+	Course *fbnd.Course
+}
+
+func (c cell) Title() string {
+	return c.Course.NameLong + " / " + c.Course.ProfessorLong
+}
+
+func (c cell) Text() string {
+	room := c.Course.Room
+	if room == "Unknown" {
+		room = "???"
+	}
+	return strings.Join([]string{c.Course.NameShort, string(c.Course.Lesson), c.Course.ProfessorShort, room}, " ")
+}
+
+const (
+	firstHour = 8
+	lastHour  = 20
+)
+
+var weekdayNames = map[time.Weekday]string{
+	time.Monday:    "Mo",
+	time.Tuesday:   "Di",
+	time.Wednesday: "Mi",
+	time.Thursday:  "Do",
+	time.Friday:    "Fr",
+	time.Saturday:  "Sa",
+}
+
+// renderTimetable returns the start hours of all columns as well as the rows of
+// each weekday that has courses. Courses that overlap are put into separate rows,
+// in which case the weekday cell of the first row spans all rows of the weekday.
+func renderTimetable(courses []fbnd.Course) (hours []int, days [][]row) {
+	start, end := firstHour, lastHour
+	for _, v := range courses {
+		if v.Time.HourStart < start {
+			start = v.Time.HourStart
+		}
+		if v.Time.HourEnd > end {
+			end = v.Time.HourEnd
+		}
+	}
+	for h := start; h < end; h++ {
+		hours = append(hours, h)
+	}
+
+	byWeekday := make(map[time.Weekday][]fbnd.Course)
+	for _, v := range courses {
+		byWeekday[v.Time.Weekday] = append(byWeekday[v.Time.Weekday], v)
+	}
+
+	for weekday := time.Monday; weekday <= time.Saturday; weekday++ {
+		dayCourses := byWeekday[weekday]
+		if len(dayCourses) == 0 {
+			continue
+		}
+		sort.SliceStable(dayCourses, func(i, j int) bool {
+			return dayCourses[i].Time.HourStart < dayCourses[j].Time.HourStart
+		})
+
+		// Put each course into the first row where it does not overlap with another one.
+		var lanes [][]fbnd.Course
+		for _, v := range dayCourses {
+			placed := false
+			for i, lane := range lanes {
+				if lane[len(lane)-1].Time.HourEnd <= v.Time.HourStart {
+					lanes[i] = append(lane, v)
+					placed = true
+					break
+				}
+			}
+			if !placed {
+				lanes = append(lanes, []fbnd.Course{v})
+			}
+		}
+
+		var rows []row
+		for i, lane := range lanes {
+			var r row
+			if i == 0 {
+				r.Weekday = weekdayNames[weekday]
+				r.Rowspan = len(lanes)
+			}
+			hour := start
+			for i := range lane {
+				for ; hour < lane[i].Time.HourStart; hour++ {
+					r.Cells = append(r.Cells, cell{Span: 1})
+				}
+				r.Cells = append(r.Cells, cell{Span: lane[i].Time.HourEnd - lane[i].Time.HourStart, Course: &lane[i]})
+				hour = lane[i].Time.HourEnd
+			}
+			for ; hour < end; hour++ {
+				r.Cells = append(r.Cells, cell{Span: 1})
+			}
+			rows = append(rows, r)
+		}
+		days = append(days, rows)
+	}
+
+	return hours, days
+}
+
+var pageTemplate = template.Must(template.New("page").Funcs(template.FuncMap{
+	"inc": func(i int) int { return i + 1 },
+}).Parse(`<!DOCTYPE html>
+<html lang="de">
+<head><meta charset="utf-8"><title>Stundenplan FB03</title></head>
+<body>
+<form method="post" action="stundenplan.php">
+	<div class="form-check form-check-inline">
+		<input class="form-check-input" type="radio" name="Lage" id="inlineWintersemester" value="WS"{{if eq .Cycle "Winter"}} checked{{end}}>
+		<label class="form-check-label" for="inlineWintersemester">Wintersemester {{.Year}}</label>
+	</div>
+	<div class="form-check form-check-inline">
+		<input class="form-check-input" type="radio" name="Lage" id="inlineSommersemester" value="SS"{{if eq .Cycle "Summer"}} checked{{end}}>
+		<label class="form-check-label" for="inlineSommersemester">Sommersemester {{.Year}}</label>
+	</div>
+	<select class="form-control" id="select_S" name="SR">
+		<optgroup label="Bachelor">
+		{{- range .Bachelor}}
+			<option value="{{.ID}}">Bachelor {{.Name}} ({{.Semester.Term}} Semester)</option>
+		{{- end}}
+		</optgroup>
+		<optgroup label="Master">
+		{{- range .Master}}
+			<option value="{{.ID}}">Master {{.Name}} ({{.Semester.Term}} Semester)</option>
+		{{- end}}
+		</optgroup>
+	</select>
+</form>
+{{- if .Hours}}
+<table class="table table-bordered">
+	<thead>
+		<tr>
+			<th></th>
+			{{- range .Hours}}
+			<th>{{.}}-{{inc .}}</th>
+			{{- end}}
+		</tr>
+	</thead>
+	<tbody>
+	{{- range .Days}}
+		{{- range .}}
+		<tr>
+			{{- if .Weekday}}
+			<td class="text-center"{{if gt .Rowspan 1}} rowspan="{{.Rowspan}}"{{end}}>{{.Weekday}}</td>
+			{{- end}}
+			{{- range .Cells}}
+			{{- if .Course}}
+			<td colspan="{{.Span}}" title="{{.Title}}">{{.Text}}</td>
+			{{- else}}
+			<td></td>
+			{{- end}}
+			{{- end}}
+		</tr>
+		{{- end}}
+		<tr style="height: 4px"></tr>
+	{{- end}}
+	</tbody>
+</table>
+{{- end}}
+</body>
+</html>
+`))
@@ -0,0 +1,49 @@
+package fbndtest
+
+import (
+	"time"
+
+	"github.com/n9v9/fbnd"
+)
+
+// SamplePrograms returns sample degree programs that tests can share instead of declaring
+// their own: BI1 and MI1 of a winter semester and BI2 of a summer semester.
+// Each call returns a new slice, so that it can be modified.
+func SamplePrograms() []fbnd.DegreeProgram {
+	return []fbnd.DegreeProgram{
+		{ID: "BI1", Name: "Informatik", Degree: fbnd.Bachelor, Semester: fbnd.Semester{Cycle: fbnd.Winter, Year: 2022, Term: 1}},
+		{ID: "MI1", Name: "Informatik", Degree: fbnd.Master, Semester: fbnd.Semester{Cycle: fbnd.Winter, Year: 2022, Term: 1}},
+		{ID: "BI2", Name: "Informatik", Degree: fbnd.Bachelor, Semester: fbnd.Semester{Cycle: fbnd.Summer, Year: 2022, Term: 2}},
+	}
+}
+
+// SampleCourses returns sample courses that tests can share instead of declaring their own:
+// the lecture Mathematik 1 on Monday, an exercise of Programmierung 1 on Monday in a room
+// that is not known yet, and the lecture Programmierung 1 on Wednesday.
+// Each call returns a new slice, so that it can be modified.
+func SampleCourses() []fbnd.Course {
+	return []fbnd.Course{
+		{
+			NameLong: "Mathematik 1", NameShort: "MA1", ProfessorLong: "Prof. Dr. Müller", ProfessorShort: "Mül",
+			Room: "B 1.10", Lesson: fbnd.Lecture, Time: fbnd.FB03Slots.Time(time.Monday, 8, 10),
+		},
+		{
+			NameLong: "Programmierung 1", NameShort: "PR1", ProfessorLong: "Prof. Dr. Schmidt", ProfessorShort: "Sch",
+			Room: "Unknown", Lesson: fbnd.Exercise, Time: fbnd.FB03Slots.Time(time.Monday, 12, 15),
+		},
+		{
+			NameLong: "Programmierung 1", NameShort: "PR1", ProfessorLong: "Prof. Dr. Schmidt", ProfessorShort: "Sch",
+			Room: "Z 2.10", Lesson: fbnd.Lecture, Time: fbnd.FB03Slots.Time(time.Wednesday, 10, 12),
+		},
+	}
+}
+
+// NewSampleServer starts and returns a new Server that serves SamplePrograms, with all
+// SampleCourses in the timetable of BI1 and only Mathematik 1 in the one of BI2.
+// The caller should call Close when finished, to shut it down.
+func NewSampleServer() *Server {
+	return NewServer(SamplePrograms(), map[fbnd.ID][]fbnd.Course{
+		"BI1": SampleCourses(),
+		"BI2": SampleCourses()[:1],
+	})
+}
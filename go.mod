@@ -6,6 +6,7 @@ require (
 	github.com/PuerkitoBio/goquery v1.6.0
 	github.com/fatih/color v1.10.0
 	github.com/spf13/cobra v1.1.1
+	golang.org/x/sys v0.5.0
 )
 
 require (
@@ -15,5 +16,4 @@ require (
 	github.com/mattn/go-isatty v0.0.12 // indirect
 	github.com/spf13/pflag v1.0.5 // indirect
 	golang.org/x/net v0.7.0 // indirect
-	golang.org/x/sys v0.5.0 // indirect
 )
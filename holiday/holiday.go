@@ -0,0 +1,82 @@
+// Package holiday computes the public holidays of North Rhine-Westphalia,
+// the state in which the Hochschule Niederrhein is located.
+package holiday
+
+import (
+	"sort"
+	"time"
+)
+
+// Holiday is a public holiday.
+type Holiday struct {
+	// Date is midnight of the holiday in UTC; only its year, month and day are relevant.
+	Date time.Time
+	// Name is the German name of the holiday.
+	Name string
+}
+
+// NRW returns the public holidays of North Rhine-Westphalia in the given year,
+// sorted by their date.
+func NRW(year int) []Holiday {
+	easter := Easter(year)
+
+	holidays := []Holiday{
+		{Date: date(year, time.January, 1), Name: "Neujahr"},
+		{Date: easter.AddDate(0, 0, -2), Name: "Karfreitag"},
+		{Date: easter.AddDate(0, 0, 1), Name: "Ostermontag"},
+		{Date: date(year, time.May, 1), Name: "Tag der Arbeit"},
+		{Date: easter.AddDate(0, 0, 39), Name: "Christi Himmelfahrt"},
+		{Date: easter.AddDate(0, 0, 50), Name: "Pfingstmontag"},
+		{Date: easter.AddDate(0, 0, 60), Name: "Fronleichnam"},
+		{Date: date(year, time.October, 3), Name: "Tag der Deutschen Einheit"},
+		{Date: date(year, time.November, 1), Name: "Allerheiligen"},
+		{Date: date(year, time.December, 25), Name: "1. Weihnachtstag"},
+		{Date: date(year, time.December, 26), Name: "2. Weihnachtstag"},
+	}
+
+	sort.Slice(holidays, func(i, j int) bool {
+		return holidays[i].Date.Before(holidays[j].Date)
+	})
+
+	return holidays
+}
+
+// Lookup returns the public holiday of North Rhine-Westphalia that falls on the
+// day of t in the location of t, if there is one.
+func Lookup(t time.Time) (Holiday, bool) {
+	year, month, day := t.Date()
+	d := date(year, month, day)
+
+	for _, v := range NRW(year) {
+		if v.Date.Equal(d) {
+			return v, true
+		}
+	}
+
+	return Holiday{}, false
+}
+
+// Easter returns the date of Easter Sunday in the given year of the Gregorian calendar,
+// computed with the anonymous Gregorian algorithm.
+func Easter(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+
+	return date(year, time.Month(month), day)
+}
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,62 @@
+package holiday
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEaster(t *testing.T) {
+	type testCase struct {
+		year int
+		want time.Time
+	}
+
+	testCases := []testCase{
+		{year: 2000, want: date(2000, time.April, 23)},
+		{year: 2019, want: date(2019, time.April, 21)},
+		{year: 2023, want: date(2023, time.April, 9)},
+		{year: 2024, want: date(2024, time.March, 31)},
+		{year: 2025, want: date(2025, time.April, 20)},
+		{year: 2026, want: date(2026, time.April, 5)},
+	}
+
+	for _, test := range testCases {
+		if got := Easter(test.year); !got.Equal(test.want) {
+			t.Errorf("want Easter %d on %v, got %v", test.year, test.want, got)
+		}
+	}
+}
+
+func TestLookup(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type testCase struct {
+		name    string
+		instant time.Time
+		want    string
+	}
+
+	testCases := []testCase{
+		{name: "Allerheiligen", instant: time.Date(2026, time.November, 1, 10, 0, 0, 0, berlin), want: "Allerheiligen"},
+		{name: "Fronleichnam", instant: time.Date(2026, time.June, 4, 0, 0, 0, 0, berlin), want: "Fronleichnam"},
+		{name: "Karfreitag", instant: time.Date(2026, time.April, 3, 23, 59, 0, 0, berlin), want: "Karfreitag"},
+		{name: "ChristmasInBerlinButNotInUTC", instant: time.Date(2026, time.December, 25, 0, 30, 0, 0, berlin), want: "1. Weihnachtstag"},
+		{name: "RegularDay", instant: time.Date(2026, time.November, 12, 10, 0, 0, 0, berlin), want: ""},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := Lookup(test.instant)
+			if ok != (test.want != "") || got.Name != test.want {
+				t.Fatalf("want %q, got %q (found: %v)", test.want, got.Name, ok)
+			}
+		})
+	}
+
+	if got := len(NRW(2026)); got != 11 {
+		t.Fatalf("want 11 holidays, got %d", got)
+	}
+}
@@ -0,0 +1,490 @@
+package fbnd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// htmlSource is a Source that scrapes the timetable website of FB03.
+// The base URL of its Client is the URL of the stundenplan.php page.
+type htmlSource struct {
+	c *Client
+}
+
+// DegreePrograms returns all degree programs for which timetables are available
+// and that fall into the given cycle.
+// If the HTML could not be parsed, an error is returned.
+func (h htmlSource) DegreePrograms(ctx context.Context, cycle SemesterCycle) ([]DegreeProgram, error) {
+	doc, err := h.degreeProgramsDoc(ctx, cycle)
+	if err != nil {
+		return nil, err
+	}
+
+	year, parsedCycle, err := parseSemesterYear(doc)
+	if err != nil {
+		return nil, err
+	}
+	if parsedCycle != cycle {
+		// This should never happen unless the structure of the website changes.
+		return nil, fmt.Errorf("expected parsed semester cycle %s but got %s: %w", cycle, parsedCycle, ErrStructureChanged)
+	}
+
+	return parseDegreeProgramNames(doc, cycle, year)
+}
+
+// TimetableForDegreeProgram returns a Timetable that contains all courses for the given degree program.
+// The days inside Timetable are sorted by their weekday and the courses inside each day are sorted
+// by their start hour.
+// The ID can be obtained by calling DegreePrograms.
+func (h htmlSource) TimetableForDegreeProgram(ctx context.Context, id ID) (*Timetable, error) {
+	id = normalizeID(id)
+
+	doc, err := h.timeTableDoc(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	timetable, err := parseTimetable(doc, id, h.c.slots)
+	if err != nil {
+		return nil, err
+	}
+	timetable.source = h
+
+	return timetable, nil
+}
+
+// parseTimetable parses the timetable of the degree program with the given id from doc.
+// The clock times of the courses are taken from slots.
+func parseTimetable(doc *goquery.Document, id ID, slots SlotTable) (*Timetable, error) {
+	hours, err := parseHours(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	weekdays := map[string]time.Weekday{
+		"Mo": time.Monday,
+		"Di": time.Tuesday,
+		"Mi": time.Wednesday,
+		"Do": time.Thursday,
+		"Fr": time.Friday,
+		"Sa": time.Saturday,
+	}
+
+	const (
+		rowSelector  = "tbody tr:not([style])"
+		cellSelector = rowSelector + " td"
+	)
+
+	var (
+		courses        []Course
+		currentWeekday time.Weekday
+		errEach        error
+		// Maps the index of a column to the number of following rows that are
+		// still covered by a cell with a `rowspan` attribute.
+		covered = make(map[int]int)
+	)
+
+	// The markup of parallel courses is guessed, because no page with them could be
+	// saved from the website, see testdata/pages/README.md. It is assumed that they
+	// are put into multiple rows per weekday. In that case the cell of the weekday
+	// spans all of these rows, or the following rows contain no weekday cell at all.
+	// Therefore, the column of each cell is computed by skipping the columns that are
+	// covered by cells of previous rows.
+	doc.Find(rowSelector).EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		coveredInRow := covered
+		covered = make(map[int]int)
+		for col, rows := range coveredInRow {
+			if rows > 1 {
+				covered[col] = rows - 1
+			}
+		}
+
+		var col int
+		if _, ok := coveredInRow[0]; !ok && !s.Find("td").First().HasClass("text-center") {
+			// This row continues the previous weekday without a cell for it.
+			col = 1
+		}
+
+		s.Find("td").EachWithBreak(func(i int, s *goquery.Selection) bool {
+			for coveredInRow[col] > 0 {
+				col++
+			}
+
+			colspan, err := strconv.Atoi(s.AttrOr("colspan", "1"))
+			if err != nil || colspan < 1 {
+				errEach = newParseError(cellSelector+"[colspan]", "column span", s.AttrOr("colspan", ""), err)
+				return false
+			}
+			rowspan, err := strconv.Atoi(s.AttrOr("rowspan", "1"))
+			if err != nil || rowspan < 1 {
+				errEach = newParseError(cellSelector+"[rowspan]", "row span", s.AttrOr("rowspan", ""), err)
+				return false
+			}
+
+			first := col
+			col += colspan
+			if rowspan > 1 {
+				for c := first; c < col; c++ {
+					covered[c] = rowspan - 1
+				}
+			}
+
+			// Only the first element has this class so this means
+			// it contains a weekday.
+			if first == 0 && s.HasClass("text-center") {
+				text := strings.TrimSpace(s.Text())
+				if text == "" {
+					// An empty weekday cell continues the previous weekday.
+					return true
+				}
+				weekday, ok := weekdays[text]
+				if !ok {
+					errEach = newParseError(cellSelector, "weekday", s.Text(), nil)
+					return false
+				}
+				currentWeekday = weekday
+				return true
+			}
+
+			// All other `td` elements that contain a course have the attribute `title`.
+			// Parallel courses are assumed to also be stacked inside one cell, in which
+			// case each one is a child element with its own `title` attribute. Like the
+			// rows above, this markup is guessed.
+			entries := s.Find("[title]")
+			if entries.Length() == 0 {
+				if _, ok := s.Attr("title"); !ok {
+					return true
+				}
+				entries = s
+			}
+
+			start, okStart := hours[first]
+			end, okEnd := hours[col-1]
+			if !okStart || !okEnd {
+				errEach = newParseError(cellSelector, "course hours", outerHTML(s),
+					fmt.Errorf("column %d to %d has no matching header", first, col-1))
+				return false
+			}
+
+			entries.EachWithBreak(func(_ int, s *goquery.Selection) bool {
+				course, err := parseCourse(s, cellSelector)
+				if err != nil {
+					errEach = err
+					return false
+				}
+
+				course.Time = slots.Time(currentWeekday, start.HourStart, end.HourEnd)
+				courses = append(courses, course)
+
+				return true
+			})
+
+			return errEach == nil
+		})
+		return errEach == nil
+	})
+	if errEach != nil {
+		return nil, errEach
+	}
+
+	// Sort the courses by weekdays and then by their start hour.
+	weekdaysOrder := map[time.Weekday]int{
+		time.Monday:    0,
+		time.Tuesday:   1,
+		time.Wednesday: 2,
+		time.Thursday:  3,
+		time.Friday:    4,
+		time.Saturday:  5,
+	}
+
+	// Map each course to its weekday.
+	timetable := make(map[time.Weekday][]Course)
+	for _, v := range courses {
+		timetable[v.Time.Weekday] = append(timetable[v.Time.Weekday], v)
+	}
+
+	days := make([]TimetableDay, 0, len(timetable))
+	for weekday, courses := range timetable {
+		// Sort the courses within each day by their start hour.
+		sort.SliceStable(courses, func(i, j int) bool {
+			return courses[i].Time.HourStart < courses[j].Time.HourStart
+		})
+		days = append(days, TimetableDay{
+			Weekday: weekday,
+			Courses: courses,
+		})
+	}
+
+	// Sort the days by their weekday.
+	sort.SliceStable(days, func(i, j int) bool {
+		return weekdaysOrder[days[i].Weekday] < weekdaysOrder[days[j].Weekday]
+	})
+
+	year, cycle, err := parseSemesterYear(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	// Try to find the DegreeProgram as it might not be possible, see FillDegreeProgram for more.
+	var selected *DegreeProgram
+	names, err := parseDegreeProgramNames(doc, cycle, year)
+	if err != nil {
+		return nil, err
+	}
+	for i := range names {
+		if names[i].ID == id {
+			selected = &names[i]
+			break
+		}
+	}
+
+	return &Timetable{
+		DegreeProgram: selected,
+		Days:          days,
+		id:            id,
+		oldCycle:      cycle,
+	}, nil
+}
+
+// parseCourse parses a Course without its Time from the element s that is found with selector.
+func parseCourse(s *goquery.Selection, selector string) (Course, error) {
+	// The title is structured like `<long name> / <long professor name>`.
+	title := s.AttrOr("title", "")
+	fields := strings.Split(title, "/")
+	if len(fields) < 2 {
+		return Course{}, newParseError(selector+"[title]", "course title", title, nil)
+	}
+	nameLong := strings.TrimSpace(fields[0])
+	professorLong := strings.TrimSpace(fields[1])
+
+	// The text is structured like `<short name> <lesson> <short professor name> <room>`.
+	fields = strings.Fields(strings.TrimSpace(s.Text()))
+	if len(fields) < 4 {
+		return Course{}, newParseError(selector, "course", outerHTML(s), nil)
+	}
+
+	// Rooms may contain spaces, like `Z 2.10`.
+	room := strings.Join(fields[3:], " ")
+	if room == "???" {
+		room = "Unknown"
+	}
+
+	return Course{
+		NameLong:       nameLong,
+		NameShort:      fields[0],
+		ProfessorLong:  professorLong,
+		ProfessorShort: fields[2],
+		Room:           room,
+		Lesson:         Lesson(fields[1]),
+	}, nil
+}
+
+// parseHours returns a map that maps the index of each `th` element to its containing Time.
+// This way, getting the Time for a `td` element can be done by indexing
+// the map with the index of the `td` element.
+func parseHours(doc *goquery.Document) (map[int]Time, error) {
+	const hourSelector = "thead tr th:not(:first-child)"
+
+	var (
+		hours   = make(map[int]Time)
+		errEach error
+	)
+
+	doc.Find(hourSelector).EachWithBreak(func(i int, s *goquery.Selection) bool {
+		// The text is structured like `<start hour>-<end hour>`.
+		fields := strings.Split(strings.TrimSpace(s.Text()), "-")
+		if len(fields) != 2 {
+			errEach = newParseError(hourSelector, "hours", s.Text(), nil)
+			return false
+		}
+		start, err := strconv.Atoi(strings.TrimSpace(fields[0]))
+		if err != nil {
+			errEach = newParseError(hourSelector, "start hour", s.Text(), err)
+			return false
+		}
+		end, err := strconv.Atoi(strings.TrimSpace(fields[1]))
+		if err != nil {
+			errEach = newParseError(hourSelector, "end hour", s.Text(), err)
+			return false
+		}
+
+		// We need i+1 instead of i because we skipped the first `th` element with `:not(:first-child)`.
+		hours[i+1] = Time{
+			HourStart: start,
+			HourEnd:   end,
+		}
+
+		return true
+	})
+	if errEach == nil && len(hours) == 0 {
+		errEach = newParseError(hourSelector, "hours", "", errors.New("no hours found"))
+	}
+
+	return hours, errEach
+}
+
+func parseDegreeProgramNames(doc *goquery.Document, cycle SemesterCycle, year int) ([]DegreeProgram, error) {
+	// All available degree programs are structured in the following way:
+	// <select id="select_S">
+	//     <optgroup label="Bachelor">
+	//         <option value="<ID>">Bachelor <degree program name> (<semester term> Semester)</option>
+	//         ...
+	//     </optgroup>
+	//         <option value="<ID>">Master <degree program name> (<semester term> Semester)</option>
+	//         ...
+	//     <optgroup label="Master">
+	//     </optgroup>
+	// </select>
+	degreeSelector := `#select_S > optgroup[label="Bachelor"] option, #select_S > optgroup[label="Master"] option`
+
+	r := regexp.MustCompile(`(Bachelor|Master) +(.*?) +\((\d+)`)
+
+	var (
+		programs []DegreeProgram
+		errEach  error
+	)
+
+	doc.Find(degreeSelector).EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		id, exists := s.Attr("value")
+		if !exists {
+			// This should never happen unless the structure of the site changes.
+			errEach = newParseError(degreeSelector, "degree program ID", outerHTML(s), nil)
+			return false
+		}
+
+		groups := r.FindStringSubmatch(strings.TrimSpace(s.Text()))
+		if groups == nil {
+			// This should never happen unless the structure of the site changes.
+			errEach = newParseError(degreeSelector, "degree program name", s.Text(), nil)
+			return false
+		}
+		term, err := strconv.Atoi(groups[3])
+		if err != nil {
+			// This should never happen unless the structure of the site changes.
+			errEach = newParseError(degreeSelector, "semester term", s.Text(), err)
+			return false
+		}
+
+		var degree Degree
+		if groups[1] == "Bachelor" {
+			degree = Bachelor
+		} else {
+			degree = Master
+		}
+
+		programs = append(programs, DegreeProgram{
+			ID:     ID(strings.ToUpper(id)),
+			Name:   groups[2],
+			Degree: degree,
+			Semester: Semester{
+				Cycle: cycle,
+				Term:  term,
+				Year:  year,
+			},
+		})
+
+		return true
+	})
+
+	return programs, errEach
+}
+
+// parseSemesterYear looks for the radio box in doc that describes the semester,
+// either winter or summer and that is checked.
+// It returns the year of the semester, the SemesterCycle as well as any error that occurred.
+func parseSemesterYear(doc *goquery.Document) (year int, cycle SemesterCycle, err error) {
+	var (
+		yearText string
+		selector string
+	)
+
+	if _, ok := doc.Find(`input[id="inlineWintersemester"]`).Attr("checked"); ok {
+		selector = `label[for="inlineWintersemester"]`
+	} else if _, ok := doc.Find(`input[id="inlineSommersemester"]`).Attr("checked"); ok {
+		selector = `label[for="inlineSommersemester"]`
+	} else {
+		// We should never get here unless the structure of the website changes.
+		return 0, "", newParseError(`input[id="inlineWintersemester"], input[id="inlineSommersemester"]`,
+			"checked semester", "", errors.New("no semester is checked"))
+	}
+	yearText = strings.TrimSpace(doc.Find(selector).Text())
+
+	r := regexp.MustCompile(`^(Winter|Sommer)semester (\d{4})`)
+	groups := r.FindStringSubmatch(yearText)
+	if groups == nil {
+		return 0, "", newParseError(selector, "semester year", yearText, nil)
+	}
+
+	switch groups[1] {
+	case "Winter":
+		cycle = Winter
+	case "Sommer":
+		cycle = Summer
+	}
+
+	year, err = strconv.Atoi(groups[2])
+	if err != nil {
+		return 0, "", newParseError(selector, "semester year", yearText, err)
+	}
+	return
+}
+
+// degreeProgramsDoc fetches the HTML for the cycle and returns the parsed document.
+// If the request failed or the response could not be parsed, an error is returned.
+func (h htmlSource) degreeProgramsDoc(ctx context.Context, cycle SemesterCycle) (*goquery.Document, error) {
+	body, err := h.c.fetch(ctx, http.MethodPost, h.c.baseURL, degreeProgramsForm(cycle))
+	if err != nil {
+		return nil, err
+	}
+
+	return goquery.NewDocumentFromReader(bytes.NewReader(body))
+}
+
+// timeTableDoc fetches the HTML for the id and returns the parsed document.
+// If the request failed or the response could not be parsed, an error is returned.
+func (h htmlSource) timeTableDoc(ctx context.Context, id ID) (*goquery.Document, error) {
+	body, err := h.c.fetch(ctx, http.MethodPost, h.c.baseURL, timeTableForm(id))
+	if err != nil {
+		return nil, err
+	}
+
+	return goquery.NewDocumentFromReader(bytes.NewReader(body))
+}
+
+// degreeProgramsForm returns the form that is posted to obtain the degree programs of cycle.
+func degreeProgramsForm(cycle SemesterCycle) url.Values {
+	var semester string
+	switch cycle {
+	case Summer:
+		semester = "SS"
+	case Winter:
+		semester = "WS"
+	}
+
+	return url.Values{
+		"Lage":  []string{semester},
+		"fkt":   []string{"SR"},
+		"clear": []string{"false"},
+	}
+}
+
+// timeTableForm returns the form that is posted to obtain the timetable of id.
+func timeTableForm(id ID) url.Values {
+	return url.Values{
+		"fkt":   []string{"SR"},
+		"SR":    []string{string(id)},
+		"mode":  []string{"SR"},
+		"clear": []string{"false"},
+	}
+}
@@ -0,0 +1,103 @@
+package fbnd
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+var update = flag.Bool("update", false, "update the golden files in testdata/golden")
+
+// golden is the result of parsing a page from testdata/pages.
+// Pages that only list the degree programs have no timetable.
+type golden struct {
+	Programs  []DegreeProgram `json:"programs"`
+	Timetable *Timetable      `json:"timetable"`
+}
+
+// TestParseGolden parses the pages in testdata/pages, which are written by hand,
+// see testdata/pages/README.md, and compares the results with testdata/golden.
+func TestParseGolden(t *testing.T) {
+	type testCase struct {
+		name string
+		id   ID
+	}
+
+	testCases := []testCase{
+		{name: "winter", id: "BI3"},
+		{name: "summer", id: "BI2"},
+		{name: "multi-colspan", id: "BET3"},
+		{name: "empty-days", id: "MI1"},
+		{name: "unknown-room", id: "BWI3"},
+		{name: "unknown-lesson", id: "BI5"},
+		{name: "parallel-rows", id: "BI1"},
+		{name: "stacked-entries", id: "BI3"},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			checkGolden(t, filepath.Join("testdata", "pages", test.name+".html"), filepath.Join("testdata", "golden", test.name+".json"), test.id)
+		})
+	}
+}
+
+// checkGolden parses the page at pagePath and compares the result with the golden file.
+// If id is empty, only the degree programs of the page are parsed.
+func checkGolden(t *testing.T, pagePath, goldenPath string, id ID) {
+	t.Helper()
+
+	page, err := os.Open(pagePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer page.Close()
+
+	doc, err := goquery.NewDocumentFromReader(page)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	year, cycle, err := parseSemesterYear(doc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	result := golden{}
+	result.Programs, err = parseDegreeProgramNames(doc, cycle, year)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id != "" {
+		result.Timetable, err = parseTimetable(doc, id, FB03Slots)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = append(got, '\n')
+
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(goldenPath), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(goldenPath, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(want, got) {
+		t.Fatalf("parsed page differs from %s, run the tests with -update if this is intended:\n%s", goldenPath, got)
+	}
+}
@@ -0,0 +1,341 @@
+package ical
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/n9v9/fbnd"
+)
+
+// Filter selects the courses of a Timetable that are encoded.
+// The zero value selects all courses.
+type Filter struct {
+	// Lessons are the lessons of the selected courses. If empty, all lessons are selected.
+	Lessons []fbnd.Lesson
+	// Exclude are names of courses that are not selected. A name matches the NameShort
+	// of a course as well as all of its groups, see fbnd.Course.Group.
+	Exclude []string
+	// Groups maps the name of a course to the group that is selected for it.
+	// The group stored under the empty name is selected for all other courses.
+	// Courses that are not split into groups are always selected.
+	Groups map[string]string
+}
+
+// ParseFilter parses a Filter from the query parameters of a feed URL:
+//
+//   - lesson: lessons to select, either their code like V or their name like Lecture.
+//   - exclude: names of courses that are not selected.
+//   - group: the group to select for all courses like A, or for one course like PR1-B.
+//
+// Each parameter can be given multiple times or contain values separated by commas.
+func ParseFilter(query url.Values) (Filter, error) {
+	var f Filter
+
+	for _, v := range splitValues(query["lesson"]) {
+		lesson, err := fbnd.ParseLesson(v)
+		if err != nil {
+			return Filter{}, err
+		}
+		f.Lessons = append(f.Lessons, lesson)
+	}
+
+	f.Exclude = splitValues(query["exclude"])
+
+	for _, v := range splitValues(query["group"]) {
+		if f.Groups == nil {
+			f.Groups = make(map[string]string)
+		}
+		name, group := fbnd.Course{NameShort: v}.Group()
+		if group == "" {
+			// A group without the name of a course applies to all courses.
+			name, group = "", v
+		}
+		f.Groups[name] = group
+	}
+
+	return f, nil
+}
+
+// splitValues returns all non-empty values separated by commas.
+func splitValues(values []string) []string {
+	var res []string
+	for _, v := range values {
+		for _, s := range strings.Split(v, ",") {
+			if s = strings.TrimSpace(s); s != "" {
+				res = append(res, s)
+			}
+		}
+	}
+	return res
+}
+
+// Selects reports whether course is selected by f.
+func (f Filter) Selects(course fbnd.Course) bool {
+	if len(f.Lessons) > 0 {
+		found := false
+		for _, v := range f.Lessons {
+			if course.Lesson == v {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	name, group := course.Group()
+	for _, v := range f.Exclude {
+		if strings.EqualFold(v, course.NameShort) || strings.EqualFold(v, name) {
+			return false
+		}
+	}
+
+	if group == "" {
+		return true
+	}
+	if want, ok := f.Groups[name]; ok {
+		return strings.EqualFold(want, group)
+	}
+	if want, ok := f.Groups[""]; ok {
+		return strings.EqualFold(want, group)
+	}
+	return true
+}
+
+// Apply returns a copy of t that only contains the courses selected by f.
+// Days without any selected courses are left out.
+func (f Filter) Apply(t *fbnd.Timetable) *fbnd.Timetable {
+	filtered := &fbnd.Timetable{DegreeProgram: t.DegreeProgram}
+
+	for _, day := range t.Days {
+		var courses []fbnd.Course
+		for _, course := range day.Courses {
+			if f.Selects(course) {
+				courses = append(courses, course)
+			}
+		}
+		if len(courses) > 0 {
+			filtered.Days = append(filtered.Days, fbnd.TimetableDay{Weekday: day.Weekday, Courses: courses})
+		}
+	}
+
+	return filtered
+}
+
+// Handler serves the timetables of degree programs as iCalendar feeds that calendar
+// applications can subscribe to. The ID of the degree program is taken from the last
+// element of the path, which has to end with .ics, for example /ics/BI1.ics.
+// The query parameters filter the courses, see ParseFilter.
+//
+// Timetables are fetched from the source at most once per TTL. Each response carries
+// an ETag and a Last-Modified header, where the latter is the time at which a change
+// of the timetable was first noticed, so that clients only download changed feeds.
+//
+// A Handler is safe for concurrent use by multiple goroutines.
+type Handler struct {
+	source fbnd.Source
+	ttl    time.Duration
+	logger *log.Logger
+	now    func() time.Time
+
+	mu    sync.Mutex
+	feeds map[fbnd.ID]*feed
+	// calls are the fetches that are in progress, so that concurrent requests for
+	// the same outdated feed wait for a single fetch.
+	calls map[fbnd.ID]*feedCall
+}
+
+// feedCall is a fetch of a feed that is in progress.
+// Its result is set before done is closed.
+type feedCall struct {
+	done chan struct{}
+	feed *feed
+	err  error
+}
+
+// feed is the cached timetable of a degree program.
+type feed struct {
+	timetable *fbnd.Timetable
+	hash      [sha256.Size]byte
+	modified  time.Time
+	fetched   time.Time
+}
+
+// NewHandler returns a Handler that fetches the timetables from source, which is
+// usually an *fbnd.Client, and keeps them for ttl.
+// If logger is not nil, errors are logged to it.
+func NewHandler(source fbnd.Source, ttl time.Duration, logger *log.Logger) *Handler {
+	return &Handler{
+		source: source,
+		ttl:    ttl,
+		logger: logger,
+		now:    time.Now,
+		feeds:  make(map[fbnd.ID]*feed),
+		calls:  make(map[fbnd.ID]*feedCall),
+	}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	name := path.Base(r.URL.Path)
+	if !strings.HasSuffix(name, ".ics") || name == ".ics" {
+		http.NotFound(w, r)
+		return
+	}
+	id := fbnd.ID(strings.ToUpper(strings.TrimSuffix(name, ".ics")))
+
+	filter, err := ParseFilter(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f, err := h.feed(r.Context(), id)
+	if err != nil {
+		h.logf("could not fetch timetable of %s: %v", id, err)
+		http.Error(w, "could not fetch timetable", http.StatusBadGateway)
+		return
+	}
+	if f == nil {
+		http.Error(w, fmt.Sprintf("could find no courses for degree program with id %s", id), http.StatusNotFound)
+		return
+	}
+
+	// The stamp is the time of the last change, so that the output only changes
+	// together with the timetable.
+	var buf bytes.Buffer
+	if err := Encode(&buf, filter.Apply(f.timetable), Options{Stamp: f.modified}); err != nil {
+		h.logf("could not encode timetable of %s: %v", id, err)
+		http.Error(w, "could not encode timetable", http.StatusInternalServerError)
+		return
+	}
+
+	sum := sha256.Sum256(buf.Bytes())
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("ETag", `"`+hex.EncodeToString(sum[:16])+`"`)
+	w.Header().Set("Cache-Control", fmt.Sprintf("max-age=%d", int(h.ttl.Seconds())))
+
+	http.ServeContent(w, r, name, f.modified, bytes.NewReader(buf.Bytes()))
+}
+
+// feed returns the cached feed of the degree program with the given ID, fetching it
+// again if it is older than the TTL. Only one fetch per degree program runs at a time,
+// concurrent calls wait for its result. If the degree program has no courses, nil is
+// returned.
+func (h *Handler) feed(ctx context.Context, id fbnd.ID) (*feed, error) {
+	for {
+		h.mu.Lock()
+		cached := h.feeds[id]
+		if cached != nil && h.now().Sub(cached.fetched) < h.ttl {
+			h.mu.Unlock()
+			return cached, nil
+		}
+		if c, ok := h.calls[id]; ok {
+			h.mu.Unlock()
+
+			select {
+			case <-c.done:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			// The request that started the fetch may have been canceled,
+			// in which case it is tried again for this one.
+			if c.err != nil && ctx.Err() == nil && (errors.Is(c.err, context.Canceled) || errors.Is(c.err, context.DeadlineExceeded)) {
+				continue
+			}
+			return c.feed, c.err
+		}
+
+		c := &feedCall{done: make(chan struct{})}
+		h.calls[id] = c
+		h.mu.Unlock()
+
+		c.feed, c.err = h.refresh(ctx, id, cached)
+
+		h.mu.Lock()
+		delete(h.calls, id)
+		h.mu.Unlock()
+		close(c.done)
+
+		return c.feed, c.err
+	}
+}
+
+// refresh fetches the feed of the degree program with the given ID and stores it.
+// If fetching fails, the outdated cached feed is returned if there is one.
+func (h *Handler) refresh(ctx context.Context, id fbnd.ID, cached *feed) (*feed, error) {
+	now := h.now()
+
+	timetable, err := h.fetch(ctx, id)
+	if err != nil {
+		if cached != nil && ctx.Err() == nil {
+			h.logf("using outdated timetable of %s: %v", id, err)
+			return cached, nil
+		}
+		return nil, err
+	}
+	if timetable == nil {
+		return nil, nil
+	}
+
+	data, err := json.Marshal(timetable)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &feed{
+		timetable: timetable,
+		hash:      sha256.Sum256(data),
+		modified:  now.UTC().Truncate(time.Second),
+		fetched:   now,
+	}
+	if cached != nil && cached.hash == f.hash {
+		f.modified = cached.modified
+	}
+
+	h.mu.Lock()
+	h.feeds[id] = f
+	h.mu.Unlock()
+
+	return f, nil
+}
+
+// fetch returns the timetable of the degree program with the given ID with its
+// DegreeProgram set, or nil if it has no courses.
+func (h *Handler) fetch(ctx context.Context, id fbnd.ID) (*fbnd.Timetable, error) {
+	timetable, err := h.source.TimetableForDegreeProgram(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(timetable.Days) == 0 {
+		return nil, nil
+	}
+	if err := timetable.FillDegreeProgramContext(ctx); err != nil {
+		return nil, err
+	}
+	return timetable, nil
+}
+
+func (h *Handler) logf(format string, v ...any) {
+	if h.logger != nil {
+		h.logger.Printf(format, v...)
+	}
+}
@@ -0,0 +1,218 @@
+package ical
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/n9v9/fbnd"
+	"github.com/n9v9/fbnd/fbndtest"
+)
+
+func TestParseFilter(t *testing.T) {
+	query, _ := url.ParseQuery("lesson=V,exercise&exclude=EN&group=A&group=PR1-B")
+
+	got, err := ParseFilter(query)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Filter{
+		Lessons: []fbnd.Lesson{fbnd.Lecture, fbnd.Exercise},
+		Exclude: []string{"EN"},
+		Groups:  map[string]string{"": "A", "PR1": "B"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("want %+v, got %+v", want, got)
+	}
+
+	if _, err := ParseFilter(url.Values{"lesson": {"X"}}); err == nil {
+		t.Fatal("want error for unknown lesson")
+	}
+
+	type testCase struct {
+		nameShort string
+		lesson    fbnd.Lesson
+		want      bool
+	}
+
+	for _, test := range []testCase{
+		{nameShort: "MA1", lesson: fbnd.Lecture, want: true},
+		{nameShort: "MA1", lesson: fbnd.Tutorial, want: false},
+		{nameShort: "EN", lesson: fbnd.Lecture, want: false},
+		{nameShort: "MA1-A", lesson: fbnd.Exercise, want: true},
+		{nameShort: "MA1-B", lesson: fbnd.Exercise, want: false},
+		{nameShort: "PR1-A", lesson: fbnd.Exercise, want: false},
+		{nameShort: "PR1-B", lesson: fbnd.Exercise, want: true},
+	} {
+		if got := want.Selects(fbnd.Course{NameShort: test.nameShort, Lesson: test.lesson}); got != test.want {
+			t.Errorf("want %s (%s) selected to be %v, got %v", test.nameShort, test.lesson, test.want, got)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	programs := []fbnd.DegreeProgram{
+		{ID: "BI1", Name: "Informatik", Degree: fbnd.Bachelor, Semester: fbnd.Semester{Cycle: fbnd.Winter, Year: 2023, Term: 1}},
+	}
+	courses := []fbnd.Course{
+		{NameLong: "Mathematik 1", NameShort: "MA1", ProfessorLong: "Prof. Dr. Müller", ProfessorShort: "Mül", Room: "B 1.10", Lesson: fbnd.Lecture, Time: fbnd.FB03Slots.Time(time.Monday, 8, 10)},
+		{NameLong: "Mathematik 1 Gruppe A", NameShort: "MA1-A", ProfessorLong: "Prof. Dr. Müller", ProfessorShort: "Mül", Room: "B 1.10", Lesson: fbnd.Exercise, Time: fbnd.FB03Slots.Time(time.Tuesday, 10, 12)},
+		{NameLong: "Mathematik 1 Gruppe B", NameShort: "MA1-B", ProfessorLong: "Dipl.-Math. Lange", ProfessorShort: "Lan", Room: "B 1.11", Lesson: fbnd.Exercise, Time: fbnd.FB03Slots.Time(time.Tuesday, 10, 12)},
+	}
+	upstream := fbndtest.NewServer(programs, map[fbnd.ID][]fbnd.Course{"BI1": courses})
+	defer upstream.Close()
+
+	now := time.Date(2023, time.October, 1, 12, 0, 0, 0, time.UTC)
+	handler := NewHandler(upstream.Client(), time.Hour, nil)
+	handler.now = func() time.Time { return now }
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	get := func(path string, header http.Header) (*http.Response, string) {
+		t.Helper()
+		req, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for k, v := range header {
+			req.Header[k] = v
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp, string(body)
+	}
+
+	resp, body := get("/ics/bi1.ics", nil)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("want status %d, got %d: %s", http.StatusOK, resp.StatusCode, body)
+	}
+	if got := resp.Header.Get("Content-Type"); got != "text/calendar; charset=utf-8" {
+		t.Fatalf("want content type of iCalendar, got %q", got)
+	}
+	if got := resp.Header.Get("Last-Modified"); got != now.Format(http.TimeFormat) {
+		t.Fatalf("want last modified %q, got %q", now.Format(http.TimeFormat), got)
+	}
+	if got := strings.Count(body, "BEGIN:VEVENT"); got != 3 {
+		t.Fatalf("want 3 events, got %d", got)
+	}
+	etag := resp.Header.Get("ETag")
+
+	// An unchanged feed is not downloaded again.
+	resp, _ = get("/ics/BI1.ics", http.Header{"If-None-Match": {etag}})
+	if resp.StatusCode != http.StatusNotModified {
+		t.Fatalf("want status %d, got %d", http.StatusNotModified, resp.StatusCode)
+	}
+
+	// Filters select the courses.
+	_, body = get("/ics/BI1.ics?group=B", nil)
+	if strings.Contains(body, "Gruppe A") || !strings.Contains(body, "Gruppe B") || !strings.Contains(body, "SUMMARY:Mathematik 1 (Lecture)") {
+		t.Fatalf("want only group B and the lecture, got:\n%s", body)
+	}
+	_, body = get("/ics/BI1.ics?lesson=U&exclude=MA1-A", nil)
+	if got := strings.Count(body, "BEGIN:VEVENT"); got != 1 || !strings.Contains(body, "Gruppe B") {
+		t.Fatalf("want only the exercise of group B, got:\n%s", body)
+	}
+
+	// Fetching an unchanged timetable after the TTL keeps the last modification.
+	now = now.Add(2 * time.Hour)
+	resp, _ = get("/ics/BI1.ics", http.Header{"If-None-Match": {etag}})
+	if resp.StatusCode != http.StatusNotModified {
+		t.Fatalf("want status %d, got %d", http.StatusNotModified, resp.StatusCode)
+	}
+
+	// A changed timetable results in a new feed.
+	courses[0].Room = "B 2.10"
+	upstream.SetTimetable("BI1", courses)
+	now = now.Add(2 * time.Hour)
+	resp, body = get("/ics/BI1.ics", http.Header{"If-None-Match": {etag}})
+	if resp.StatusCode != http.StatusOK || !strings.Contains(body, "LOCATION:B 2.10") {
+		t.Fatalf("want changed feed, got status %d:\n%s", resp.StatusCode, body)
+	}
+	if got := resp.Header.Get("Last-Modified"); got != now.Format(http.TimeFormat) {
+		t.Fatalf("want last modified %q, got %q", now.Format(http.TimeFormat), got)
+	}
+
+	for path, want := range map[string]int{
+		"/ics/XX.ics":           http.StatusNotFound,
+		"/ics/BI1":              http.StatusNotFound,
+		"/ics/BI1.ics?lesson=":  http.StatusOK,
+		"/ics/BI1.ics?lesson=X": http.StatusBadRequest,
+	} {
+		if resp, _ := get(path, nil); resp.StatusCode != want {
+			t.Errorf("want status %d for %s, got %d", want, path, resp.StatusCode)
+		}
+	}
+}
+
+// blockingSource counts the fetched timetables, which are only returned once release
+// is closed.
+type blockingSource struct {
+	calls   int32
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingSource) DegreePrograms(context.Context, fbnd.SemesterCycle) ([]fbnd.DegreeProgram, error) {
+	return nil, nil
+}
+
+func (s *blockingSource) TimetableForDegreeProgram(_ context.Context, id fbnd.ID) (*fbnd.Timetable, error) {
+	if atomic.AddInt32(&s.calls, 1) == 1 {
+		close(s.started)
+	}
+	<-s.release
+
+	return &fbnd.Timetable{
+		DegreeProgram: &fbnd.DegreeProgram{ID: id, Semester: fbnd.Semester{Cycle: fbnd.Winter, Year: 2023, Term: 1}},
+		Days:          []fbnd.TimetableDay{{Weekday: time.Monday, Courses: []fbnd.Course{{NameShort: "MA1", Time: fbnd.FB03Slots.Time(time.Monday, 8, 10)}}}},
+	}, nil
+}
+
+func TestHandlerFetchesOnce(t *testing.T) {
+	source := &blockingSource{started: make(chan struct{}), release: make(chan struct{})}
+	handler := NewHandler(source, time.Hour, nil)
+
+	var wg sync.WaitGroup
+	get := func() {
+		defer wg.Done()
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ics/BI1.ics", nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("want status %d, got %d", http.StatusOK, rec.Code)
+		}
+	}
+
+	// The other requests are started while the first one is still fetching.
+	wg.Add(1)
+	go get()
+	<-source.started
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go get()
+	}
+	// Without waiting, the other requests might only look up the feed after it has
+	// been stored. Waiting can not make the test fail if only one fetch is made.
+	time.Sleep(50 * time.Millisecond)
+	close(source.release)
+	wg.Wait()
+
+	if source.calls != 1 {
+		t.Fatalf("want 1 fetch, got %d", source.calls)
+	}
+}
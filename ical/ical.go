@@ -0,0 +1,224 @@
+// Package ical encodes timetables as iCalendar files as described in RFC 5545,
+// so that they can be imported into calendar applications.
+package ical
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/n9v9/fbnd"
+)
+
+// ErrNoDegreeProgram is returned when the DegreeProgram of a Timetable is nil,
+// because the lecture period of its semester is needed to bound the events.
+// Call fbnd.Timetable.FillDegreeProgram beforehand.
+var ErrNoDegreeProgram = errors.New("timetable has no degree program")
+
+// Options configure how a Timetable is encoded.
+type Options struct {
+	// Stamp is the time at which the calendar was created.
+	// If zero, the current time is used. Set it to a fixed value to get the
+	// same output for the same timetable.
+	Stamp time.Time
+}
+
+const (
+	dateTimeFormat    = "20060102T150405"
+	utcDateTimeFormat = "20060102T150405Z"
+)
+
+// Encode writes t as an iCalendar to w.
+//
+// Each Course becomes one event that repeats weekly during the lecture period of
+// the semester of t, see fbnd.Semester.LectureCalendar. Days without lectures are
+// excluded from the recurrence. The UID of each event only depends on the degree
+// program, the semester and the name, lesson and time of the course, so that
+// changes of the room or professor update the existing event when importing again.
+func Encode(w io.Writer, t *fbnd.Timetable, opts Options) error {
+	if t.DegreeProgram == nil {
+		return ErrNoDegreeProgram
+	}
+
+	stamp := opts.Stamp
+	if stamp.IsZero() {
+		stamp = time.Now()
+	}
+
+	program := t.DegreeProgram
+	lectures := program.Semester.LectureCalendar().Lectures
+
+	// Group the occurrences by their course while keeping the order of the courses.
+	var (
+		courses     []fbnd.Course
+		occurrences = make(map[fbnd.Course][]fbnd.Occurrence)
+	)
+	for _, v := range t.Occurrences(lectures.Start, lectures.End.AddDate(0, 0, 1)) {
+		if _, ok := occurrences[v.Course]; !ok {
+			courses = append(courses, v.Course)
+		}
+		occurrences[v.Course] = append(occurrences[v.Course], v)
+	}
+
+	e := &encoder{w: bufio.NewWriter(w)}
+
+	e.line("BEGIN:VCALENDAR")
+	e.line("VERSION:2.0")
+	e.line("PRODID:-//n9v9//fbnd//EN")
+	e.line("CALSCALE:GREGORIAN")
+	e.line("METHOD:PUBLISH")
+	e.property("X-WR-CALNAME", fmt.Sprintf("%s %s (Semester %d)", program.Degree, program.Name, program.Semester.Term))
+	e.property("X-WR-TIMEZONE", fbnd.Location.String())
+	e.lines(vTimezone)
+
+	uids := make(map[string]int)
+	for _, course := range courses {
+		uid := courseUID(program, course)
+		// Identical keys are possible, for example for stacked parallel courses
+		// in different rooms, so they are numbered.
+		if n := uids[uid]; n > 0 {
+			uids[uid]++
+			uid = fmt.Sprintf("%s-%d", uid, n)
+		} else {
+			uids[uid] = 1
+		}
+
+		e.event(uid, course, occurrences[course], stamp)
+	}
+
+	e.line("END:VCALENDAR")
+
+	if e.err != nil {
+		return e.err
+	}
+	return e.w.Flush()
+}
+
+// courseUID returns a UID that stays the same for a course across timetable updates.
+func courseUID(program *fbnd.DegreeProgram, course fbnd.Course) string {
+	key := fmt.Sprintf("%s|%s|%d|%s|%s|%d|%d|%d",
+		program.ID, program.Semester.Cycle, program.Semester.Year,
+		course.NameShort, course.Lesson,
+		course.Time.Weekday, course.Time.HourStart, course.Time.HourEnd)
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:16]) + "@fbnd"
+}
+
+type encoder struct {
+	w   *bufio.Writer
+	err error
+}
+
+// event writes a VEVENT that starts with the first and repeats weekly until the last
+// occurrence. Weeks between them without an occurrence are excluded.
+func (e *encoder) event(uid string, course fbnd.Course, occurrences []fbnd.Occurrence, stamp time.Time) {
+	first, last := occurrences[0], occurrences[len(occurrences)-1]
+
+	starts := make(map[string]bool, len(occurrences))
+	for _, v := range occurrences {
+		starts[v.Start.Format(dateTimeFormat)] = true
+	}
+	var exdates []string
+	for d := first.Start; d.Before(last.Start); d = d.AddDate(0, 0, 7) {
+		if start := d.Format(dateTimeFormat); !starts[start] {
+			exdates = append(exdates, start)
+		}
+	}
+
+	tzid := ";TZID=" + fbnd.Location.String()
+
+	e.line("BEGIN:VEVENT")
+	e.line("UID:" + uid)
+	e.line("DTSTAMP:" + stamp.UTC().Format(utcDateTimeFormat))
+	e.line("DTSTART" + tzid + ":" + first.Start.Format(dateTimeFormat))
+	e.line("DTEND" + tzid + ":" + first.End.Format(dateTimeFormat))
+	e.line("RRULE:FREQ=WEEKLY;UNTIL=" + last.Start.UTC().Format(utcDateTimeFormat))
+	if len(exdates) > 0 {
+		e.line("EXDATE" + tzid + ":" + strings.Join(exdates, ","))
+	}
+	e.property("SUMMARY", fmt.Sprintf("%s (%s)", course.NameLong, course.Lesson))
+	if course.Room != "" && course.Room != "Unknown" {
+		e.property("LOCATION", course.Room)
+	}
+	e.property("DESCRIPTION", strings.Join([]string{
+		course.NameShort,
+		course.Lesson.String(),
+		course.ProfessorLong,
+	}, "\n"))
+	e.line("END:VEVENT")
+}
+
+// property writes a property whose value is text that needs to be escaped.
+func (e *encoder) property(name, value string) {
+	e.line(name + ":" + escape(value))
+}
+
+func (e *encoder) lines(lines []string) {
+	for _, v := range lines {
+		e.line(v)
+	}
+}
+
+// line writes a content line terminated by CRLF. Lines longer than 75 octets are
+// folded by inserting a CRLF followed by a space, without splitting UTF-8 sequences.
+func (e *encoder) line(line string) {
+	if e.err != nil {
+		return
+	}
+
+	const maxLength = 75
+
+	var sb strings.Builder
+	length := 0
+	for _, r := range line {
+		size := len(string(r))
+		if length+size > maxLength {
+			sb.WriteString("\r\n ")
+			length = 1
+		}
+		sb.WriteRune(r)
+		length += size
+	}
+	sb.WriteString("\r\n")
+
+	_, e.err = e.w.WriteString(sb.String())
+}
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`;`, `\;`,
+	`,`, `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+)
+
+// escape escapes a TEXT value.
+func escape(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// vTimezone describes fbnd.Location, which is Europe/Berlin.
+var vTimezone = []string{
+	"BEGIN:VTIMEZONE",
+	"TZID:Europe/Berlin",
+	"BEGIN:DAYLIGHT",
+	"TZOFFSETFROM:+0100",
+	"TZOFFSETTO:+0200",
+	"TZNAME:CEST",
+	"DTSTART:19700329T020000",
+	"RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU",
+	"END:DAYLIGHT",
+	"BEGIN:STANDARD",
+	"TZOFFSETFROM:+0200",
+	"TZOFFSETTO:+0100",
+	"TZNAME:CET",
+	"DTSTART:19701025T030000",
+	"RRULE:FREQ=YEARLY;BYMONTH=10;BYDAY=-1SU",
+	"END:STANDARD",
+	"END:VTIMEZONE",
+}
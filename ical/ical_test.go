@@ -0,0 +1,97 @@
+package ical
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/n9v9/fbnd"
+)
+
+func testTimetable() *fbnd.Timetable {
+	return &fbnd.Timetable{
+		DegreeProgram: &fbnd.DegreeProgram{
+			ID:       "BI3",
+			Name:     "Informatik",
+			Degree:   fbnd.Bachelor,
+			Semester: fbnd.Semester{Cycle: fbnd.Winter, Year: 2023, Term: 3},
+		},
+		Days: []fbnd.TimetableDay{{
+			Weekday: time.Monday,
+			Courses: []fbnd.Course{{
+				NameLong:       "Algorithmen, Datenstrukturen; Teil 1",
+				NameShort:      "ALD",
+				ProfessorLong:  "Prof. Dr. Albrecht",
+				ProfessorShort: "Alb",
+				Room:           "B 1.10",
+				Lesson:         fbnd.Lecture,
+				Time:           fbnd.FB03Slots.Time(time.Monday, 8, 10),
+			}},
+		}},
+	}
+}
+
+func TestEncode(t *testing.T) {
+	var buf bytes.Buffer
+	stamp := time.Date(2023, time.September, 1, 12, 0, 0, 0, time.UTC)
+	if err := Encode(&buf, testTimetable(), Options{Stamp: stamp}); err != nil {
+		t.Fatal(err)
+	}
+	got := buf.String()
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"DTSTAMP:20230901T120000Z\r\n",
+		// The lecture period of the winter semester 2023 starts on Monday, 2023-09-25.
+		"DTSTART;TZID=Europe/Berlin:20230925T081500\r\n",
+		"DTEND;TZID=Europe/Berlin:20230925T094500\r\n",
+		"RRULE:FREQ=WEEKLY;UNTIL=20240122T071500Z\r\n",
+		// The Mondays of the Christmas break.
+		"EXDATE;TZID=Europe/Berlin:20231225T081500,20240101T081500\r\n",
+		`SUMMARY:Algorithmen\, Datenstrukturen\; Teil 1 (Lecture)` + "\r\n",
+		"LOCATION:B 1.10\r\n",
+		`DESCRIPTION:ALD\nLecture\nProf. Dr. Albrecht` + "\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("want output to contain %q, got:\n%s", want, got)
+		}
+	}
+
+	// Encoding the same timetable again results in the same calendar.
+	var again bytes.Buffer
+	if err := Encode(&again, testTimetable(), Options{Stamp: stamp}); err != nil {
+		t.Fatal(err)
+	}
+	if again.String() != got {
+		t.Fatal("want identical output for identical input")
+	}
+}
+
+func TestEncodeWithoutDegreeProgram(t *testing.T) {
+	timetable := testTimetable()
+	timetable.DegreeProgram = nil
+
+	if err := Encode(&bytes.Buffer{}, timetable, Options{}); !errors.Is(err, ErrNoDegreeProgram) {
+		t.Fatalf("want error %v, got %v", ErrNoDegreeProgram, err)
+	}
+}
+
+func TestLineFolding(t *testing.T) {
+	var buf bytes.Buffer
+	e := &encoder{w: bufio.NewWriter(&buf)}
+	e.property("SUMMARY", strings.Repeat("ä", 50))
+	e.w.Flush()
+
+	for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Fatalf("want lines of at most 75 octets, got %d: %q", len(line), line)
+		}
+	}
+	if unfolded := strings.ReplaceAll(buf.String(), "\r\n ", ""); unfolded != "SUMMARY:"+strings.Repeat("ä", 50)+"\r\n" {
+		t.Fatalf("unfolded line differs: %q", unfolded)
+	}
+}
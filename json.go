@@ -0,0 +1,58 @@
+package fbnd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// jsonSource is a Source that queries the JSON API of fbnd serve instead of scraping HTML.
+// The base URL of its Client is the base URL of the API, see WithJSONAPI
+// for the expected endpoints.
+type jsonSource struct {
+	c *Client
+}
+
+// DegreePrograms returns all degree programs for which timetables are available
+// and that fall into the given cycle.
+func (j jsonSource) DegreePrograms(ctx context.Context, cycle SemesterCycle) ([]DegreeProgram, error) {
+	var programs []DegreeProgram
+
+	query := url.Values{"cycle": []string{strings.ToLower(string(cycle))}}
+	if err := j.get(ctx, "/programs?"+query.Encode(), &programs); err != nil {
+		return nil, err
+	}
+
+	return programs, nil
+}
+
+// TimetableForDegreeProgram returns a Timetable that contains all courses for the given degree program.
+func (j jsonSource) TimetableForDegreeProgram(ctx context.Context, id ID) (*Timetable, error) {
+	id = normalizeID(id)
+
+	var timetable Timetable
+	if err := j.get(ctx, "/programs/"+url.PathEscape(string(id))+"/timetable", &timetable); err != nil {
+		return nil, err
+	}
+
+	j.c.slots.fill(&timetable)
+	timetable.id = id
+	timetable.source = j
+	if timetable.DegreeProgram != nil {
+		timetable.oldCycle = timetable.DegreeProgram.Semester.Cycle
+	}
+
+	return &timetable, nil
+}
+
+// get fetches the path relative to the base URL and decodes the JSON response into v.
+func (j jsonSource) get(ctx context.Context, path string, v any) error {
+	body, err := j.c.fetch(ctx, http.MethodGet, strings.TrimSuffix(j.c.baseURL, "/")+path, nil)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(body, v)
+}
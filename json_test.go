@@ -0,0 +1,73 @@
+package fbnd
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClientJSONAPI(t *testing.T) {
+	program := DegreeProgram{
+		ID:       "BI1",
+		Name:     "Informatik",
+		Degree:   Bachelor,
+		Semester: Semester{Cycle: Winter, Year: 2022, Term: 1},
+	}
+	timetable := Timetable{
+		DegreeProgram: &program,
+		Days: []TimetableDay{{
+			Weekday: time.Monday,
+			Courses: []Course{{NameShort: "MA1", Lesson: Lecture, Time: Time{Weekday: time.Monday, HourStart: 8, HourEnd: 10}}},
+		}},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/programs", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("cycle"); got != "winter" {
+			t.Errorf("want cycle winter, got %s", got)
+		}
+		json.NewEncoder(w).Encode([]DegreeProgram{program})
+	})
+	mux.HandleFunc("/programs/BI1/timetable", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(timetable)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client := NewClient(WithBaseURL(server.URL), WithJSONAPI())
+	ctx := context.Background()
+
+	programs, err := client.DegreePrograms(ctx, Winter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(programs) != 1 || programs[0] != program {
+		t.Fatalf("want %v, got %v", []DegreeProgram{program}, programs)
+	}
+
+	got, err := client.TimetableForDegreeProgram(ctx, "bi1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := got.FillDegreeProgram(); err != nil {
+		t.Fatal(err)
+	}
+	if *got.DegreeProgram != program {
+		t.Fatalf("want degree program %v, got %v", program, *got.DegreeProgram)
+	}
+	if len(got.Days) != 1 || got.Days[0].Courses[0].NameShort != "MA1" {
+		t.Fatalf("unexpected days %v", got.Days)
+	}
+
+	// The JSON contains no clock times, so they are taken from the slot table.
+	if want, got := FB03Slots.Time(time.Monday, 8, 10), got.Days[0].Courses[0].Time; want != got {
+		t.Fatalf("want time %v, got %v", want, got)
+	}
+
+	if _, err := client.TimetableForDegreeProgram(ctx, "XX"); err == nil {
+		t.Fatal("want error for unknown degree program, got nil")
+	}
+}
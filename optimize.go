@@ -0,0 +1,221 @@
+package fbnd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// Criterion is a criterion by which Optimize ranks schedules.
+type Criterion string
+
+const (
+	// FewestDays prefers schedules with courses on fewer weekdays.
+	FewestDays Criterion = "days"
+	// SmallestGaps prefers schedules with fewer free hours between the courses of a day.
+	SmallestGaps Criterion = "gaps"
+	// LatestStart prefers schedules whose first course of the week starts later.
+	LatestStart Criterion = "start"
+)
+
+// ParseCriterion parses a Criterion from its name, like days.
+func ParseCriterion(s string) (Criterion, error) {
+	switch c := Criterion(s); c {
+	case FewestDays, SmallestGaps, LatestStart:
+		return c, nil
+	default:
+		return "", fmt.Errorf("unknown criterion %q, expected %s, %s or %s", s, FewestDays, SmallestGaps, LatestStart)
+	}
+}
+
+// Schedule is a week of courses in which no courses overlap, as returned by Optimize.
+type Schedule struct {
+	Courses []Course `json:"courses"`
+	// Days is the number of weekdays with courses.
+	Days int `json:"days"`
+	// Gaps is the number of free hours between the courses of each day, summed up.
+	Gaps int `json:"gaps"`
+	// Start is the hour at which the first course of the week starts.
+	Start int `json:"start"`
+}
+
+func newSchedule(courses []Course) Schedule {
+	s := Schedule{Courses: append([]Course(nil), courses...)}
+	sort.SliceStable(s.Courses, func(i, j int) bool {
+		a, b := s.Courses[i].Time, s.Courses[j].Time
+		if a.Weekday != b.Weekday {
+			return a.Weekday < b.Weekday
+		}
+		return a.HourStart < b.HourStart
+	})
+
+	for i, v := range s.Courses {
+		if i == 0 || v.Time.HourStart < s.Start {
+			s.Start = v.Time.HourStart
+		}
+	}
+
+	var end int
+	for i, v := range s.Courses {
+		if i == 0 || v.Time.Weekday != s.Courses[i-1].Time.Weekday {
+			s.Days++
+			end = v.Time.HourEnd
+			continue
+		}
+		if v.Time.HourStart > end {
+			s.Gaps += v.Time.HourStart - end
+		}
+		if v.Time.HourEnd > end {
+			end = v.Time.HourEnd
+		}
+	}
+
+	return s
+}
+
+// Timetable returns a Timetable with the courses of s.
+func (s Schedule) Timetable() *Timetable {
+	days := make(map[time.Weekday][]Course)
+	for _, v := range s.Courses {
+		days[v.Time.Weekday] = append(days[v.Time.Weekday], v)
+	}
+
+	t := &Timetable{}
+	for weekday, courses := range days {
+		t.Days = append(t.Days, TimetableDay{Weekday: weekday, Courses: courses})
+	}
+	return Merge([]*Timetable{t}, func(Course) bool { return true })
+}
+
+// better reports whether s ranks before other by the first criterion in which they differ.
+func (s Schedule) better(other Schedule, criteria []Criterion) bool {
+	for _, c := range criteria {
+		switch c {
+		case FewestDays:
+			if s.Days != other.Days {
+				return s.Days < other.Days
+			}
+		case SmallestGaps:
+			if s.Gaps != other.Gaps {
+				return s.Gaps < other.Gaps
+			}
+		case LatestStart:
+			if s.Start != other.Start {
+				return s.Start > other.Start
+			}
+		}
+	}
+	return false
+}
+
+// Optimize returns the best schedules that contain courses, ranked by criteria, of which
+// earlier ones take precedence. At most limit schedules are returned, or all if limit is 0.
+//
+// Exercises and internships that are offered in several parallel groups, like PR1-A and
+// PR1-B, are alternatives of which exactly one group with all its courses is part of each
+// schedule. All other courses are part of every schedule. Only combinations of groups in
+// which no courses overlap are considered, see Conflicts, so the result is empty if
+// there are none.
+func Optimize(courses []Course, criteria []Criterion, limit int) []Schedule {
+	fixed, choices := alternatives(courses)
+	if len(Conflicts(fixed)) > 0 {
+		return nil
+	}
+
+	var best []Schedule
+	var search func(chosen []Course, choices [][][]Course)
+	search = func(chosen []Course, choices [][][]Course) {
+		if len(choices) == 0 {
+			s := newSchedule(chosen)
+			i := sort.Search(len(best), func(i int) bool { return s.better(best[i], criteria) })
+			if limit > 0 && i >= limit {
+				return
+			}
+			best = append(best, Schedule{})
+			copy(best[i+1:], best[i:])
+			best[i] = s
+			if limit > 0 && len(best) > limit {
+				best = best[:limit]
+			}
+			return
+		}
+
+		for _, group := range choices[0] {
+			if overlapsAny(chosen, group) {
+				continue
+			}
+			// Limit the capacity, so that the groups of one choice do not share memory.
+			search(append(chosen[:len(chosen):len(chosen)], group...), choices[1:])
+		}
+	}
+	search(fixed, choices)
+
+	return best
+}
+
+// alternatives splits courses into the ones that are part of every schedule and the
+// choices between parallel groups of exercises and internships. Each choice contains
+// the courses of each of its groups. Duplicate courses are removed.
+func alternatives(courses []Course) (fixed []Course, choices [][][]Course) {
+	type key struct {
+		name   string
+		lesson Lesson
+	}
+
+	var (
+		seen   = make(map[Course]bool)
+		keys   []key
+		groups = make(map[key][]string)
+		byKey  = make(map[key]map[string][]Course)
+	)
+	for _, v := range courses {
+		if seen[v] {
+			continue
+		}
+		seen[v] = true
+
+		name, group := v.Group()
+		if group == "" || (v.Lesson != Exercise && v.Lesson != Internship) {
+			fixed = append(fixed, v)
+			continue
+		}
+
+		k := key{name: name, lesson: v.Lesson}
+		if byKey[k] == nil {
+			keys = append(keys, k)
+			byKey[k] = make(map[string][]Course)
+		}
+		if byKey[k][group] == nil {
+			groups[k] = append(groups[k], group)
+		}
+		byKey[k][group] = append(byKey[k][group], v)
+	}
+
+	for _, k := range keys {
+		sort.Strings(groups[k])
+		if len(groups[k]) == 1 {
+			fixed = append(fixed, byKey[k][groups[k][0]]...)
+			continue
+		}
+
+		var choice [][]Course
+		for _, group := range groups[k] {
+			choice = append(choice, byKey[k][group])
+		}
+		choices = append(choices, choice)
+	}
+
+	return fixed, choices
+}
+
+// overlapsAny reports whether one of courses overlaps with one of others.
+func overlapsAny(courses, others []Course) bool {
+	for _, a := range courses {
+		for _, b := range others {
+			if hoursOverlap(a.Time, b.Time) {
+				return true
+			}
+		}
+	}
+	return false
+}
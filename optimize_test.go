@@ -0,0 +1,91 @@
+package fbnd
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestOptimize(t *testing.T) {
+	db := Course{NameShort: "DB", Lesson: Lecture, Time: FB03Slots.Time(time.Monday, 8, 10)}
+	dbA := Course{NameShort: "DB-A", Lesson: Exercise, Time: FB03Slots.Time(time.Monday, 10, 12)}
+	dbB := Course{NameShort: "DB-B", Lesson: Exercise, Time: FB03Slots.Time(time.Wednesday, 8, 10)}
+	seA := Course{NameShort: "SE-A", Lesson: Internship, Time: FB03Slots.Time(time.Monday, 8, 10)}
+	seB := Course{NameShort: "SE-B", Lesson: Internship, Time: FB03Slots.Time(time.Monday, 12, 14)}
+	seC := Course{NameShort: "SE-C", Lesson: Internship, Time: FB03Slots.Time(time.Tuesday, 10, 12)}
+	courses := []Course{db, dbA, dbB, seA, seB, seC}
+
+	type testCase struct {
+		name     string
+		courses  []Course
+		criteria []Criterion
+		limit    int
+		want     []Schedule
+	}
+
+	tests := []testCase{
+		{
+			name:     "fewest days",
+			courses:  courses,
+			criteria: []Criterion{FewestDays, SmallestGaps},
+			want: []Schedule{
+				{Courses: []Course{db, dbA, seB}, Days: 1, Gaps: 0, Start: 8},
+				{Courses: []Course{db, dbA, seC}, Days: 2, Gaps: 0, Start: 8},
+				{Courses: []Course{db, seB, dbB}, Days: 2, Gaps: 2, Start: 8},
+				{Courses: []Course{db, seC, dbB}, Days: 3, Gaps: 0, Start: 8},
+			},
+		},
+		{
+			name:     "smallest gaps",
+			courses:  courses,
+			criteria: []Criterion{SmallestGaps, FewestDays},
+			want: []Schedule{
+				{Courses: []Course{db, dbA, seB}, Days: 1, Gaps: 0, Start: 8},
+				{Courses: []Course{db, dbA, seC}, Days: 2, Gaps: 0, Start: 8},
+				{Courses: []Course{db, seC, dbB}, Days: 3, Gaps: 0, Start: 8},
+				{Courses: []Course{db, seB, dbB}, Days: 2, Gaps: 2, Start: 8},
+			},
+		},
+		{
+			name:     "latest start",
+			courses:  []Course{dbA, dbB, seC},
+			criteria: []Criterion{LatestStart},
+			want: []Schedule{
+				{Courses: []Course{dbA, seC}, Days: 2, Gaps: 0, Start: 10},
+				{Courses: []Course{seC, dbB}, Days: 2, Gaps: 0, Start: 8},
+			},
+		},
+		{
+			name:     "limit",
+			courses:  courses,
+			criteria: []Criterion{FewestDays, SmallestGaps},
+			limit:    1,
+			want: []Schedule{
+				{Courses: []Course{db, dbA, seB}, Days: 1, Gaps: 0, Start: 8},
+			},
+		},
+		{
+			name:     "no conflict-free schedule",
+			courses:  []Course{db, seA},
+			criteria: []Criterion{FewestDays},
+			want:     nil,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Optimize(test.courses, test.criteria, test.limit); !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("want %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestParseCriterion(t *testing.T) {
+	if c, err := ParseCriterion("gaps"); err != nil || c != SmallestGaps {
+		t.Fatalf("want %v, got %v (%v)", SmallestGaps, c, err)
+	}
+	if _, err := ParseCriterion("rooms"); err == nil {
+		t.Fatal("want error, got nil")
+	}
+}
@@ -0,0 +1,110 @@
+package fbnd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// StatusError is returned when the server responds with a status code other than 200.
+type StatusError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Status     string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected response status %s for %s %s", e.Status, e.Method, e.URL)
+}
+
+// RetryPolicy describes how often and how long a Client waits before retrying
+// a request that failed with a 5xx or 429 status code or timed out.
+// The delay before each retry doubles, starting at BaseDelay, up to MaxDelay,
+// and a random duration of at most that delay is waited, so that many clients
+// do not retry at the same time.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of times a request is sent.
+	// Values less than 2 disable retries.
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultRetryPolicy retries a request two times.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    5 * time.Second,
+}
+
+// delay returns the time to wait before the given retry, starting at 1.
+func (p RetryPolicy) delay(retry int) time.Duration {
+	d := p.BaseDelay
+	for i := 1; i < retry && d < p.MaxDelay; i++ {
+		d *= 2
+	}
+	if p.MaxDelay > 0 && d > p.MaxDelay {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(d) + 1))
+}
+
+// retryable reports whether a request that failed with err should be retried.
+func retryable(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode >= 500 || statusErr.StatusCode == http.StatusTooManyRequests
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// rateLimiter spaces out requests so that at most one request is started per interval.
+// It is safe for concurrent use by multiple goroutines.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// wait blocks until the next request may be started or ctx is done.
+func (l *rateLimiter) wait(ctx context.Context) error {
+	l.mu.Lock()
+	now := time.Now()
+	start := l.next
+	if start.Before(now) {
+		start = now
+	}
+	// Reserve the slot before waiting, so that concurrent callers queue up behind it.
+	l.next = start.Add(l.interval)
+	l.mu.Unlock()
+
+	return sleep(ctx, time.Until(start))
+}
+
+// sleep blocks for d or until ctx is done, in which case the error of ctx is returned.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,105 @@
+package fbnd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestClientRetry(t *testing.T) {
+	type testCase struct {
+		name         string
+		statusCodes  []int
+		wantAttempts int
+		wantStatus   int
+	}
+
+	testCases := []testCase{
+		{
+			name:         "SucceedsAfterServerErrors",
+			statusCodes:  []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK},
+			wantAttempts: 3,
+		},
+		{
+			name:         "GivesUpAfterMaxAttempts",
+			statusCodes:  []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError, http.StatusOK},
+			wantAttempts: 3,
+			wantStatus:   http.StatusInternalServerError,
+		},
+		{
+			name:         "DoesNotRetryClientErrors",
+			statusCodes:  []int{http.StatusNotFound, http.StatusOK},
+			wantAttempts: 1,
+			wantStatus:   http.StatusNotFound,
+		},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.name, func(t *testing.T) {
+			var attempts int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(test.statusCodes[attempts])
+				w.Write([]byte("[]"))
+				attempts++
+			}))
+			defer server.Close()
+
+			client := NewClient(
+				WithBaseURL(server.URL),
+				WithJSONAPI(),
+				WithRetry(RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}),
+			)
+
+			_, err := client.DegreePrograms(context.Background(), Winter)
+
+			if attempts != test.wantAttempts {
+				t.Fatalf("want %d attempts, got %d", test.wantAttempts, attempts)
+			}
+
+			var statusErr *StatusError
+			if test.wantStatus == 0 {
+				if err != nil {
+					t.Fatal(err)
+				}
+			} else if !errors.As(err, &statusErr) || statusErr.StatusCode != test.wantStatus {
+				t.Fatalf("want status error %d, got %v", test.wantStatus, err)
+			}
+		})
+	}
+}
+
+func TestClientRateLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("[]"))
+	}))
+	defer server.Close()
+
+	const (
+		requests = 4
+		interval = 20 * time.Millisecond
+	)
+
+	client := NewClient(WithBaseURL(server.URL), WithJSONAPI(), WithRateLimit(interval))
+
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.DegreePrograms(context.Background(), Winter); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if elapsed, want := time.Since(start), (requests-1)*interval; elapsed < want {
+		t.Fatalf("want requests to take at least %v, took %v", want, elapsed)
+	}
+}
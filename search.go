@@ -0,0 +1,70 @@
+package fbnd
+
+import (
+	"sort"
+	"strings"
+)
+
+// SearchResult is a Course found by Search together with the DegreeProgram it is part of.
+type SearchResult struct {
+	DegreeProgram DegreeProgram `json:"degreeProgram"`
+	Course        Course        `json:"course"`
+}
+
+// Search returns the courses of the timetables whose NameLong, NameShort, ProfessorLong
+// or Room contain query. The DegreeProgram of each Timetable must be set, like it is
+// for the ones returned by AllTimetables. A course that is part of multiple degree
+// programs is found once for each of them. The results are sorted by the ID of their
+// degree program and the time of their course.
+//
+// The search ignores case and umlauts, so "mueller" as well as "muller" find "Müller".
+func Search(timetables []*Timetable, query string) []SearchResult {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil
+	}
+
+	var results []SearchResult
+	for _, t := range timetables {
+		for _, day := range t.Days {
+			for _, course := range day.Courses {
+				if matchesAny(query, course.NameLong, course.NameShort, course.ProfessorLong, course.Room) {
+					results = append(results, SearchResult{DegreeProgram: *t.DegreeProgram, Course: course})
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.DegreeProgram.ID != b.DegreeProgram.ID {
+			return a.DegreeProgram.ID < b.DegreeProgram.ID
+		}
+		if a.Course.Time.Weekday != b.Course.Time.Weekday {
+			return a.Course.Time.Weekday < b.Course.Time.Weekday
+		}
+		return a.Course.Time.HourStart < b.Course.Time.HourStart
+	})
+
+	return results
+}
+
+var (
+	// Umlauts can either be written without their dots or with a following e.
+	withoutDots = strings.NewReplacer("ä", "a", "ö", "o", "ü", "u", "ß", "ss")
+	withE       = strings.NewReplacer("ä", "ae", "ö", "oe", "ü", "ue", "ß", "ss")
+)
+
+// matchesAny reports whether one of fields contains query, ignoring case and umlauts.
+func matchesAny(query string, fields ...string) bool {
+	query = strings.ToLower(query)
+	for _, replacer := range []*strings.Replacer{withoutDots, withE} {
+		q := replacer.Replace(query)
+		for _, field := range fields {
+			if strings.Contains(replacer.Replace(strings.ToLower(field)), q) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package fbnd
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSearch(t *testing.T) {
+	bi1 := DegreeProgram{ID: "BI1", Semester: Semester{Cycle: Winter, Year: 2022, Term: 1}}
+	ei1 := DegreeProgram{ID: "EI1", Semester: Semester{Cycle: Winter, Year: 2022, Term: 1}}
+
+	ma1 := Course{NameLong: "Mathematik 1", NameShort: "MA1", ProfessorLong: "Prof. Dr. Müller", Room: "B 1.10", Time: FB03Slots.Time(time.Monday, 8, 10)}
+	get := Course{NameLong: "Grundlagen der Elektrotechnik", NameShort: "GET", ProfessorLong: "Prof. Dr.-Ing. Fischer", Room: "Z 2.10", Time: FB03Slots.Time(time.Tuesday, 10, 12)}
+	ger := Course{NameLong: "Gestaltung", NameShort: "GES", ProfessorLong: "Dipl.-Des. Groß", Room: "Z 3.01", Time: FB03Slots.Time(time.Friday, 8, 10)}
+
+	timetables := []*Timetable{
+		{DegreeProgram: &ei1, Days: []TimetableDay{{Weekday: time.Monday, Courses: []Course{ma1}}, {Weekday: time.Tuesday, Courses: []Course{get}}}},
+		{DegreeProgram: &bi1, Days: []TimetableDay{{Weekday: time.Monday, Courses: []Course{ma1}}, {Weekday: time.Friday, Courses: []Course{ger}}}},
+	}
+
+	type testCase struct {
+		query string
+		want  []ID
+	}
+
+	testCases := []testCase{
+		{query: "mathematik", want: []ID{"BI1", "EI1"}},
+		{query: "muller", want: []ID{"BI1", "EI1"}},
+		{query: "MUELLER", want: []ID{"BI1", "EI1"}},
+		{query: "gross", want: []ID{"BI1"}},
+		{query: "z 2.10", want: []ID{"EI1"}},
+		{query: "get", want: []ID{"EI1"}},
+		{query: "physik", want: nil},
+		{query: " ", want: nil},
+	}
+
+	for _, test := range testCases {
+		t.Run(test.query, func(t *testing.T) {
+			got := Search(timetables, test.query)
+			if len(got) != len(test.want) {
+				t.Fatalf("want results for %v, got %v", test.want, got)
+			}
+			for i, v := range got {
+				if v.DegreeProgram.ID != test.want[i] {
+					t.Fatalf("want result %d for %s, got %s", i, test.want[i], v.DegreeProgram.ID)
+				}
+			}
+		})
+	}
+}